@@ -0,0 +1,10 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package hookbusterv1 contains the gRPC service that receivers implement to
+// accept events from grpc:// and grpcs:// targets, along with the generated
+// Go client and server stubs.
+package hookbusterv1
+
+//go:generate sh -c "cd ../.. && buf generate"
@@ -0,0 +1,197 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package attachments downloads the files shared in Webex messages and
+// inlines or stores them for forwarding. Mercury only carries encrypted
+// file references, so files are fetched through the REST API, which
+// returns them decrypted.
+package attachments
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	webex "github.com/WebexCommunity/webex-go-sdk/v2"
+	"github.com/WebexCommunity/webex-go-sdk/v2/webexsdk"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// File is one entry of a rewritten data.files list. URL points at the
+// stored copy in dir and s3 mode; Data holds the base64 content in inline
+// mode. A file that could not be fetched or stored has only its source,
+// name and Error.
+type File struct {
+	Name        string `json:"name,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Data        string `json:"data,omitempty"`
+	SourceURL   string `json:"sourceUrl"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Download is an open file download. The caller reads and closes Body.
+type Download struct {
+	ContentType        string
+	ContentDisposition string
+	ContentLength      int64 // -1 when unknown
+	Body               io.ReadCloser
+}
+
+// Source looks up a message's files and downloads them.
+type Source interface {
+	// MessageFiles returns the content URLs of a message's files.
+	MessageFiles(messageID string) ([]string, error)
+
+	// Download opens a file by its content URL.
+	Download(contentURL string) (*Download, error)
+}
+
+// sdkSource is the Source backed by the Webex messages and contents APIs.
+type sdkSource struct {
+	client *webex.WebexClient
+}
+
+// NewSDKSource returns a Source that uses client's messages and contents
+// APIs.
+func NewSDKSource(client *webex.WebexClient) Source {
+	return sdkSource{client: client}
+}
+
+func (s sdkSource) MessageFiles(messageID string) ([]string, error) {
+	msg, err := s.client.Messages().Get(messageID)
+	if err != nil {
+		return nil, err
+	}
+	return msg.Files, nil
+}
+
+// Download requests the file itself rather than through the contents API,
+// which reads the whole body into memory before the size can be checked.
+func (s sdkSource) Download(contentURL string) (*Download, error) {
+	resp, err := s.client.Core().RequestURL(http.MethodGet, contentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching content: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, webexsdk.NewAPIError(resp, body)
+	}
+	return &Download{
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		ContentLength:      resp.ContentLength,
+		Body:               resp.Body,
+	}, nil
+}
+
+// Fetcher downloads a message's files and inlines or stores each one.
+type Fetcher struct {
+	source  Source
+	store   Store // nil in inline mode
+	maxSize int
+}
+
+// New returns a Fetcher for the given options, creating the store the mode
+// needs.
+func New(opts *config.AttachmentOptions, source Source) (*Fetcher, error) {
+	f := &Fetcher{source: source, maxSize: config.DefaultAttachmentMaxSizeMB << 20}
+	if opts.MaxSizeMB > 0 {
+		f.maxSize = opts.MaxSizeMB << 20
+	}
+
+	var err error
+	switch opts.Mode {
+	case config.AttachmentsInline:
+	case config.AttachmentsDir:
+		f.store, err = newDirStore(opts.Dir)
+	case config.AttachmentsS3:
+		f.store, err = newS3Store(opts.S3)
+	default:
+		err = fmt.Errorf("unknown attachments mode %q", opts.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Fetch returns the files of the message with the given activity ID in
+// the Hydra cluster of its room. Files that fail individually are reported
+// in their entry's Error; an error is returned only when the message
+// itself cannot be looked up.
+func (f *Fetcher) Fetch(activityID, cluster string) ([]File, error) {
+	urls, err := f.source.MessageFiles(hydra.IDIn(cluster, hydra.Message, activityID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up message files: %w", err)
+	}
+
+	files := make([]File, len(urls))
+	for i, u := range urls {
+		files[i] = f.fetchFile(activityID, i, u)
+	}
+	return files, nil
+}
+
+// fetchFile downloads one file and inlines or stores it. A file over the
+// size limit is rejected by its Content-Length before any of it is read,
+// and a file without one is read no further than one byte past the limit.
+func (f *Fetcher) fetchFile(activityID string, n int, contentURL string) File {
+	file := File{SourceURL: contentURL}
+	dl, err := f.source.Download(contentURL)
+	if err != nil {
+		file.Error = fmt.Sprintf("download failed: %v", err)
+		return file
+	}
+	defer dl.Body.Close()
+	file.Name = fileName(dl.ContentDisposition, n)
+	file.ContentType = dl.ContentType
+	if dl.ContentLength > int64(f.maxSize) {
+		file.Error = fmt.Sprintf("file is %d bytes, over the %d MB limit", dl.ContentLength, f.maxSize>>20)
+		return file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(dl.Body, int64(f.maxSize)+1))
+	if err != nil {
+		file.Error = fmt.Sprintf("download failed: %v", err)
+		return file
+	}
+	if len(data) > f.maxSize {
+		file.Error = fmt.Sprintf("file is over the %d MB limit", f.maxSize>>20)
+		return file
+	}
+	file.Size = len(data)
+
+	if f.store == nil {
+		file.Data = base64.StdEncoding.EncodeToString(data)
+		return file
+	}
+	key := fmt.Sprintf("%s/%d-%s", activityID, n, file.Name)
+	if file.URL, err = f.store.Put(key, dl.ContentType, data); err != nil {
+		file.Error = fmt.Sprintf("store failed: %v", err)
+	}
+	return file
+}
+
+// fileName returns the file name from a Content-Disposition header, made
+// safe to use as a path segment, or "file" when there is none.
+func fileName(disposition string, n int) string {
+	name := "file"
+	if _, params, err := mime.ParseMediaType(disposition); err == nil && params["filename"] != "" {
+		name = params["filename"]
+	}
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = fmt.Sprintf("file-%d", n)
+	}
+	return name
+}
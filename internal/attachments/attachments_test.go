@@ -0,0 +1,258 @@
+package attachments
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+const testActivityID = "92db3be0-43bd-11e6-8ae9-dd5b3dfc565d"
+
+// fakeFile is one file served by fakeSource. A nil body serves data.
+type fakeFile struct {
+	contentType   string
+	disposition   string
+	data          []byte
+	contentLength int64
+	body          io.Reader
+}
+
+// fakeSource serves files from memory.
+type fakeSource struct {
+	messageID string
+	files     map[string]fakeFile
+	order     []string
+}
+
+func (s *fakeSource) MessageFiles(messageID string) ([]string, error) {
+	s.messageID = messageID
+	return s.order, nil
+}
+
+func (s *fakeSource) Download(contentURL string) (*Download, error) {
+	file, ok := s.files[contentURL]
+	if !ok {
+		return nil, errors.New("404 not found")
+	}
+	body := file.body
+	if body == nil {
+		body = bytes.NewReader(file.data)
+	}
+	return &Download{
+		ContentType:        file.contentType,
+		ContentDisposition: file.disposition,
+		ContentLength:      file.contentLength,
+		Body:               io.NopCloser(body),
+	}, nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		files: map[string]fakeFile{
+			"https://webexapis.com/v1/contents/a": {
+				contentType:   "text/plain",
+				disposition:   `attachment; filename="notes.txt"`,
+				data:          []byte("hello"),
+				contentLength: 5,
+			},
+			"https://webexapis.com/v1/contents/big": {
+				contentType:   "application/octet-stream",
+				data:          make([]byte, 2<<20),
+				contentLength: -1,
+			},
+		},
+		order: []string{
+			"https://webexapis.com/v1/contents/a",
+			"https://webexapis.com/v1/contents/big",
+			"https://webexapis.com/v1/contents/missing",
+		},
+	}
+}
+
+// countingReader yields zero bytes forever and counts how many were read.
+type countingReader struct{ n int64 }
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.n += int64(len(p))
+	return len(p), nil
+}
+
+// ── Fetcher tests ───────────────────────────────────────────────────────
+
+func TestFetch_Inline(t *testing.T) {
+	src := newFakeSource()
+	f, err := New(&config.AttachmentOptions{Mode: config.AttachmentsInline, MaxSizeMB: 1}, src)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	files, err := f.Fetch(testActivityID, hydra.DefaultCluster)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	if want := "Y2lzY29zcGFyazovL3VzL01FU1NBR0UvOTJkYjNiZTAtNDNiZC0xMWU2LThhZTktZGQ1YjNkZmM1NjVk"; src.messageID != want {
+		t.Errorf("message id = %q, want %q", src.messageID, want)
+	}
+	if len(files) != 3 {
+		t.Fatalf("files = %d, want 3", len(files))
+	}
+	if got := files[0]; got.Name != "notes.txt" || got.Size != 5 || got.Data != base64.StdEncoding.EncodeToString([]byte("hello")) || got.Error != "" {
+		t.Errorf("file 0 = %+v", got)
+	}
+	if got := files[1]; got.Data != "" || !strings.Contains(got.Error, "over the 1 MB limit") {
+		t.Errorf("file 1 = %+v, want size limit error", got)
+	}
+	if got := files[2]; got.SourceURL != "https://webexapis.com/v1/contents/missing" || !strings.Contains(got.Error, "download failed") {
+		t.Errorf("file 2 = %+v, want download error", got)
+	}
+}
+
+func TestFetch_SizeLimitStopsReading(t *testing.T) {
+	declared, unknown := &countingReader{}, &countingReader{}
+	src := &fakeSource{
+		files: map[string]fakeFile{
+			"https://webexapis.com/v1/contents/declared": {contentLength: 5 << 20, body: declared},
+			"https://webexapis.com/v1/contents/unknown":  {contentLength: -1, body: unknown},
+		},
+		order: []string{"https://webexapis.com/v1/contents/declared", "https://webexapis.com/v1/contents/unknown"},
+	}
+	f, err := New(&config.AttachmentOptions{Mode: config.AttachmentsInline, MaxSizeMB: 1}, src)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	files, err := f.Fetch(testActivityID, hydra.DefaultCluster)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	if !strings.Contains(files[0].Error, "5242880 bytes, over the 1 MB limit") || declared.n != 0 {
+		t.Errorf("declared file = %+v after reading %d bytes, want rejected unread", files[0], declared.n)
+	}
+	if !strings.Contains(files[1].Error, "over the 1 MB limit") || unknown.n > 1<<20+1 {
+		t.Errorf("unknown-length file = %+v after reading %d bytes, want rejected at the limit", files[1], unknown.n)
+	}
+}
+
+func TestFetch_Dir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "files")
+	f, err := New(&config.AttachmentOptions{Mode: config.AttachmentsDir, Dir: dir}, newFakeSource())
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	files, err := f.Fetch(testActivityID, hydra.DefaultCluster)
+	if err != nil {
+		t.Fatalf("Fetch() error: %v", err)
+	}
+
+	want := filepath.Join(dir, testActivityID, "0-notes.txt")
+	if files[0].URL != "file://"+filepath.ToSlash(want) || files[0].Data != "" {
+		t.Errorf("file 0 = %+v, want url of %s", files[0], want)
+	}
+	if data, err := os.ReadFile(want); err != nil || string(data) != "hello" {
+		t.Errorf("stored file = %q, %v", data, err)
+	}
+	if files[1].Error != "" || files[1].Name != "file" {
+		t.Errorf("file 1 = %+v, want stored under the default name", files[1])
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		disposition string
+		want        string
+	}{
+		{`attachment; filename="report.pdf"`, "report.pdf"},
+		{`attachment; filename="../../etc/passwd"`, "passwd"},
+		{`attachment; filename="a\b.txt"`, "b.txt"},
+		{`attachment; filename=".."`, "file-3"},
+		{"", "file"},
+	}
+	for _, tt := range tests {
+		if got := fileName(tt.disposition, 3); got != tt.want {
+			t.Errorf("fileName(%q) = %q, want %q", tt.disposition, got, tt.want)
+		}
+	}
+}
+
+// ── S3 store tests ──────────────────────────────────────────────────────
+
+func TestSigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation.
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got, want := hex.EncodeToString(key), "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"; got != want {
+		t.Errorf("signingKey() = %s, want %s", got, want)
+	}
+}
+
+func TestS3Store_Put(t *testing.T) {
+	var gotPath, gotAuth, gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer srv.Close()
+
+	t.Setenv("TEST_S3_KEY", "AKIDEXAMPLE")
+	t.Setenv("TEST_S3_SECRET", "secret")
+	s, err := newS3Store(&config.S3Options{
+		Endpoint:     srv.URL,
+		Bucket:       "files",
+		Region:       "eu-west-1",
+		Prefix:       "webex/",
+		AccessKeyEnv: "TEST_S3_KEY",
+		SecretKeyEnv: "TEST_S3_SECRET",
+	})
+	if err != nil {
+		t.Fatalf("newS3Store() error: %v", err)
+	}
+	s.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	url, err := s.Put("msg/0-my report.txt", "text/plain", []byte("hello"))
+	if err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if want := srv.URL + "/files/webex/msg/0-my%20report.txt"; url != want {
+		t.Errorf("url = %q, want %q", url, want)
+	}
+	if gotPath != "/files/webex/msg/0-my%20report.txt" || gotBody != "hello" || gotType != "text/plain" {
+		t.Errorf("request = %s %q %q", gotPath, gotType, gotBody)
+	}
+	wantAuth := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250601/eu-west-1/s3/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="
+	if !strings.HasPrefix(gotAuth, wantAuth) || len(gotAuth) != len(wantAuth)+64 {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+}
+
+func TestS3Store_PutError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	s, err := newS3Store(&config.S3Options{Endpoint: srv.URL, Bucket: "files", PublicURL: "https://cdn.example.com/"})
+	if err != nil {
+		t.Fatalf("newS3Store() error: %v", err)
+	}
+	if s.publicURL != "https://cdn.example.com" {
+		t.Errorf("publicURL = %q", s.publicURL)
+	}
+	if _, err := s.Put("k", "", []byte("x")); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put() error = %v, want AccessDenied", err)
+	}
+}
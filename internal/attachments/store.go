@@ -0,0 +1,196 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package attachments
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// Store keeps a copy of a file and returns the URL it can be read from.
+// Keys are slash-separated relative paths.
+type Store interface {
+	Put(key, contentType string, data []byte) (string, error)
+}
+
+// dirStore writes files below a local directory and returns file:// URLs.
+type dirStore struct {
+	dir string
+}
+
+func newDirStore(dir string) (*dirStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachments dir %q: %w", dir, err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create attachments dir: %w", err)
+	}
+	return &dirStore{dir: abs}, nil
+}
+
+func (s *dirStore) Put(key, _ string, data []byte) (string, error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(p)}).String(), nil
+}
+
+// s3UploadTimeout bounds a single object upload.
+const s3UploadTimeout = 60 * time.Second
+
+// s3Store uploads files to an S3-compatible bucket with path-style PUT
+// requests signed with AWS Signature Version 4.
+type s3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	prefix    string
+	accessKey string
+	secretKey string
+	publicURL string
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newS3Store(o *config.S3Options) (*s3Store, error) {
+	if o == nil {
+		return nil, fmt.Errorf("attachments: s3 options are required")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(o.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint %q: %w", o.Endpoint, err)
+	}
+	s := &s3Store{
+		endpoint:  endpoint,
+		bucket:    o.Bucket,
+		region:    config.DefaultS3Region,
+		prefix:    o.Prefix,
+		accessKey: os.Getenv(o.AccessKeyEnv),
+		secretKey: os.Getenv(o.SecretKeyEnv),
+		publicURL: strings.TrimRight(o.PublicURL, "/"),
+		client:    &http.Client{Timeout: s3UploadTimeout},
+		now:       time.Now,
+	}
+	if o.Region != "" {
+		s.region = o.Region
+	}
+	if s.publicURL == "" {
+		s.publicURL = endpoint.String() + "/" + uriEncode(o.Bucket)
+	}
+	return s, nil
+}
+
+func (s *s3Store) Put(key, contentType string, data []byte) (string, error) {
+	objectPath := uriEncodePath(s.prefix + key)
+	canonicalURI := strings.TrimRight(s.endpoint.EscapedPath(), "/") + "/" + uriEncode(s.bucket) + "/" + objectPath
+
+	req, err := http.NewRequest(http.MethodPut, s.endpoint.Scheme+"://"+s.endpoint.Host+canonicalURI, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, canonicalURI, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("s3 upload returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return s.publicURL + "/" + objectPath, nil
+}
+
+// sign adds the x-amz headers and the SigV4 Authorization header to req.
+func (s *s3Store) sign(req *http.Request, canonicalURI string, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method, canonicalURI, "", canonicalHeaders, signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signature := hex.EncodeToString(hmacSHA256(signingKey(s.secretKey, date, s.region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// signingKey derives the SigV4 signing key for a date, region and service.
+func signingKey(secret, date, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secret), date)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uriEncodePath URI-encodes each segment of a slash-separated path.
+func uriEncodePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode percent-encodes everything but the RFC 3986 unreserved
+// characters, as SigV4 requires.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
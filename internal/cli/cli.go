@@ -6,9 +6,10 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"regexp"
 	"strings"
 
 	"github.com/tejzpr/webex-go-hookbuster/internal/config"
@@ -17,6 +18,9 @@ import (
 
 var scanner = bufio.NewScanner(os.Stdin)
 
+// ErrEOF is returned by every prompt once stdin is exhausted.
+var ErrEOF = errors.New("EOF reached")
+
 // prompt prints a question and reads a line from stdin.
 func prompt(question string) (string, error) {
 	fmt.Print(display.Question(question))
@@ -26,7 +30,7 @@ func prompt(question string) (string, error) {
 	if err := scanner.Err(); err != nil {
 		return "", err
 	}
-	return "", fmt.Errorf("EOF reached")
+	return "", ErrEOF
 }
 
 // RequestToken prompts the user for a Webex access token.
@@ -41,103 +45,253 @@ func RequestToken() (string, error) {
 	return answer, nil
 }
 
-// RequestTarget prompts the user for the forwarding target hostname/IP.
-func RequestTarget() (string, error) {
-	answer, err := prompt(`Enter a target you will forward messages to (e.g. "localhost"): `)
+// RequestPipelineName prompts for a pipeline name. An empty answer
+// accepts defaultName.
+func RequestPipelineName(defaultName string) (string, error) {
+	answer, err := prompt(fmt.Sprintf("Enter a pipeline name [%s]: ", defaultName))
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultName, nil
+	}
+	return answer, nil
+}
+
+// envNamePattern matches a portable environment variable name.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RequestTokenEnv prompts for the env var name a saved config will read the
+// token from. An empty answer accepts defaultName.
+func RequestTokenEnv(defaultName string) (string, error) {
+	answer, err := prompt(fmt.Sprintf("Env var that will hold this token in hookbuster.yml [%s]: ", defaultName))
 	if err != nil {
 		return "", err
 	}
 	if answer == "" {
-		return "", fmt.Errorf("target empty")
+		answer = defaultName
+	}
+	if !envNamePattern.MatchString(answer) {
+		return "", fmt.Errorf("not a valid env var name")
 	}
 	return answer, nil
 }
 
-// RequestPort prompts the user for the forwarding port number.
-func RequestPort() (int, error) {
-	answer, err := prompt("Enter a port you will forward messages to: ")
+// RequestTargetURL prompts for a forwarding target URL. A bare host or
+// host:port is taken as an HTTP target. An empty answer returns "" so the
+// caller can stop asking for more targets.
+func RequestTargetURL() (string, error) {
+	answer, err := prompt(`Enter a target URL (e.g. "http://localhost:8080", "kafka://broker:9092/topic"), empty to finish: `)
 	if err != nil {
-		return 0, err
+		return "", err
 	}
 	if answer == "" {
-		return 0, fmt.Errorf("port empty")
+		return "", nil
+	}
+	if !strings.Contains(answer, "://") {
+		answer = "http://" + answer
+	}
+	return answer, nil
+}
+
+// RequestMode prompts for a pipeline's forwarding mode. An empty answer
+// selects round-robin.
+func RequestMode() (string, error) {
+	answer, err := prompt(fmt.Sprintf("Select mode [ r - %s, f - %s ] (default %s): ",
+		config.ModeRoundRobin, config.ModeFanout, config.ModeRoundRobin))
+	if err != nil {
+		return "", err
+	}
+	switch answer {
+	case "", "r", config.ModeRoundRobin:
+		return config.ModeRoundRobin, nil
+	case "f", config.ModeFanout:
+		return config.ModeFanout, nil
+	}
+	return "", fmt.Errorf("invalid mode")
+}
+
+// Confirm asks a yes/no question. An empty answer returns def.
+func Confirm(question string, def bool) (bool, error) {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+	answer, err := prompt(fmt.Sprintf("%s [%s]: ", question, choices))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
 	}
-	port, err := strconv.Atoi(answer)
+	return false, fmt.Errorf("answer y or n")
+}
+
+// RequestPath prompts for a file path. An empty answer accepts defaultPath.
+func RequestPath(question, defaultPath string) (string, error) {
+	answer, err := prompt(fmt.Sprintf("%s [%s]: ", question, defaultPath))
 	if err != nil {
-		return 0, fmt.Errorf("not a number")
+		return "", err
+	}
+	if answer == "" {
+		return defaultPath, nil
 	}
-	return port, nil
+	return answer, nil
 }
 
-// ResourceResult is returned by RequestResource.
-// If AllResources is true, the user selected firehose mode ("all").
-// Otherwise, Single contains the chosen resource.
+// ResourceResult is returned by RequestResources.
+// If AllResources is true, the user selected firehose mode ("all") and
+// Resources lists every firehose resource, followed by any opt-in
+// resources also selected.
 type ResourceResult struct {
 	AllResources bool
-	Single       *config.Resource
+	Resources    []*config.Resource
 }
 
-// RequestResource prompts the user to select a resource.
-// Returns ResourceResult indicating whether the user chose "all" or a
-// single resource.
-func RequestResource() (*ResourceResult, error) {
-	// Build the prompt string showing aliases
-	question := fmt.Sprintf(
-		"Select resource [ a - all, %s - %s, %s - %s, %s - %s, %s - %s ]: ",
-		config.Resources["rooms"].Alias, config.Resources["rooms"].Description,
-		config.Resources["messages"].Alias, config.Resources["messages"].Description,
-		config.Resources["memberships"].Alias, config.Resources["memberships"].Description,
-		config.Resources["attachmentActions"].Alias, config.Resources["attachmentActions"].Description,
-	)
+// RequestResources prompts the user to select one or more resources,
+// separated by commas. Each may be given by alias or full name; "a" or
+// "all" selects every firehose resource, plus any opt-in resources named
+// alongside it.
+func RequestResources() (*ResourceResult, error) {
+	var options []string
+	for _, name := range append(append([]string{}, config.FirehoseResourceNames...), config.OptInResourceNames...) {
+		res := config.Resources[name]
+		options = append(options, fmt.Sprintf("%s - %s", res.Alias, res.Description))
+	}
+	question := fmt.Sprintf("Select resources [ a - all, %s ] (comma-separated): ", strings.Join(options, ", "))
 
 	answer, err := prompt(question)
 	if err != nil {
 		return nil, err
 	}
-	if answer == "" {
+	parts := splitList(answer)
+	if len(parts) == 0 {
 		return nil, fmt.Errorf("response empty")
 	}
 
-	// Check for "all" alias
-	if answer == "a" {
-		return &ResourceResult{AllResources: true}, nil
+	result := &ResourceResult{}
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		if part == "a" || part == "all" {
+			result.AllResources = true
+			continue
+		}
+		res := findResource(part)
+		if res == nil {
+			return nil, fmt.Errorf("unknown resource %q", part)
+		}
+		if !seen[res.Description] {
+			seen[res.Description] = true
+			result.Resources = append(result.Resources, res)
+		}
 	}
 
-	// Find the resource by alias
-	for _, res := range config.Resources {
-		if res.Alias == answer {
-			return &ResourceResult{Single: res}, nil
+	if result.AllResources {
+		var all []*config.Resource
+		for _, name := range config.FirehoseResourceNames {
+			all = append(all, config.Resources[name])
+		}
+		for _, res := range result.Resources {
+			if !isFirehose(res.Description) {
+				all = append(all, res)
+			}
 		}
+		result.Resources = all
 	}
+	return result, nil
+}
 
-	return nil, fmt.Errorf("invalid selection")
+// isFirehose reports whether name is one of the firehose resources.
+func isFirehose(name string) bool {
+	for _, n := range config.FirehoseResourceNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
 
-// RequestEvent prompts the user to select an event from the given pool.
-func RequestEvent(events []string) (string, error) {
-	// Build option display
-	var options []string
-	for _, event := range events {
-		alias := string(event[0])
-		options = append(options, fmt.Sprintf("%s - %s", alias, event))
+// findResource matches a resource by alias or case-insensitive full name.
+func findResource(answer string) *config.Resource {
+	for _, res := range config.Resources {
+		if res.Alias == answer || strings.EqualFold(res.Description, answer) {
+			return res
+		}
 	}
+	return nil
+}
 
-	promptText := fmt.Sprintf("Select event [ %s ]: ", strings.Join(options, ", "))
+// RequestEvents prompts the user to select one or more of a resource's
+// events, separated by commas. Events are matched by full name or by an
+// unambiguous prefix; choosing "all" returns just "all".
+func RequestEvents(resource *config.Resource) ([]string, error) {
+	promptText := fmt.Sprintf("Select events for %s [ %s ] (comma-separated): ",
+		resource.Description, strings.Join(resource.Events, ", "))
 	answer, err := prompt(promptText)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if answer == "" {
-		return "", fmt.Errorf("response empty")
+	parts := splitList(answer)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("response empty")
+	}
+
+	var events []string
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		event, err := matchEvent(resource.Events, part)
+		if err != nil {
+			return nil, err
+		}
+		if event == "all" {
+			return []string{"all"}, nil
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
 	}
+	return events, nil
+}
 
-	// Match by first character alias
+// matchEvent resolves an answer to one event by case-insensitive full name,
+// or else by a prefix that only one event starts with.
+func matchEvent(events []string, answer string) (string, error) {
+	answer = strings.ToLower(answer)
+	if answer == "all" {
+		return "all", nil
+	}
+	var matches []string
 	for _, event := range events {
-		if answer == string(event[0]) {
-			fmt.Println(display.Answer(strings.ToUpper(event)))
+		lower := strings.ToLower(event)
+		if lower == answer {
 			return event, nil
 		}
+		if strings.HasPrefix(lower, answer) {
+			matches = append(matches, event)
+		}
 	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown event %q (choose from %s)", answer, strings.Join(events, ", "))
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("ambiguous event %q (matches %s)", answer, strings.Join(matches, ", "))
+}
 
-	return "", fmt.Errorf("event invalid")
+// splitList splits a comma-separated answer, dropping empty entries.
+func splitList(answer string) []string {
+	var parts []string
+	for _, part := range strings.Split(answer, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
 }
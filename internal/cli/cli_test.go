@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"strings"
 	"testing"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
 )
 
 // setInput replaces the package-level scanner with one reading from s.
@@ -46,178 +48,253 @@ func TestRequestToken_TrimSpaces(t *testing.T) {
 	}
 }
 
-// ── RequestTarget tests ─────────────────────────────────────────────────
+// ── RequestPipelineName / RequestTokenEnv tests ─────────────────────────
 
-func TestRequestTarget_Valid(t *testing.T) {
-	setInput("localhost\n")
-	target, err := RequestTarget()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+func TestRequestPipelineName(t *testing.T) {
+	setInput("\nbot\n")
+	if name, err := RequestPipelineName("pipeline-1"); err != nil || name != "pipeline-1" {
+		t.Errorf("empty answer = %q, %v; want the default", name, err)
 	}
-	if target != "localhost" {
-		t.Errorf("target = %q, want %q", target, "localhost")
+	if name, err := RequestPipelineName("pipeline-1"); err != nil || name != "bot" {
+		t.Errorf("name = %q, %v; want %q", name, err, "bot")
 	}
 }
 
-func TestRequestTarget_Empty(t *testing.T) {
-	setInput("\n")
-	_, err := RequestTarget()
-	if err == nil {
-		t.Fatal("expected error for empty target")
+func TestRequestTokenEnv(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"\n", "WEBEX_TOKEN_BOT", false},
+		{"MY_TOKEN\n", "MY_TOKEN", false},
+		{"1TOKEN\n", "", true},
+		{"MY-TOKEN\n", "", true},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		got, err := RequestTokenEnv("WEBEX_TOKEN_BOT")
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RequestTokenEnv(%q) = %q, %v; want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
 	}
 }
 
-// ── RequestPort tests ───────────────────────────────────────────────────
+// ── RequestTargetURL tests ──────────────────────────────────────────────
 
-func TestRequestPort_Valid(t *testing.T) {
-	setInput("8080\n")
-	port, err := RequestPort()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if port != 8080 {
-		t.Errorf("port = %d, want %d", port, 8080)
+func TestRequestTargetURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"http://localhost:8080/hook\n", "http://localhost:8080/hook"},
+		{"kafka://broker:9092/events\n", "kafka://broker:9092/events"},
+		{"localhost:8080\n", "http://localhost:8080"},
+		{"\n", ""},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		got, err := RequestTargetURL()
+		if err != nil || got != tt.want {
+			t.Errorf("RequestTargetURL(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
 	}
 }
 
-func TestRequestPort_Empty(t *testing.T) {
-	setInput("\n")
-	_, err := RequestPort()
-	if err == nil {
-		t.Fatal("expected error for empty port")
+// ── RequestMode / Confirm tests ─────────────────────────────────────────
+
+func TestRequestMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"\n", "roundrobin", false},
+		{"r\n", "roundrobin", false},
+		{"f\n", "fanout", false},
+		{"fanout\n", "fanout", false},
+		{"x\n", "", true},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		got, err := RequestMode()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RequestMode(%q) = %q, %v; want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
 	}
 }
 
-func TestRequestPort_NotANumber(t *testing.T) {
-	setInput("abc\n")
-	_, err := RequestPort()
-	if err == nil {
-		t.Fatal("expected error for non-numeric port")
-	}
-	if err.Error() != "not a number" {
-		t.Errorf("error = %q, want %q", err.Error(), "not a number")
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input   string
+		def     bool
+		want    bool
+		wantErr bool
+	}{
+		{"\n", true, true, false},
+		{"\n", false, false, false},
+		{"y\n", false, true, false},
+		{"YES\n", false, true, false},
+		{"n\n", true, false, false},
+		{"maybe\n", true, false, true},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		got, err := Confirm("Continue?", tt.def)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Confirm(%q, %v) = %v, %v; want %v (error %v)", tt.input, tt.def, got, err, tt.want, tt.wantErr)
+		}
 	}
 }
 
-// ── RequestResource tests ───────────────────────────────────────────────
+// ── RequestResources tests ──────────────────────────────────────────────
 
-func TestRequestResource_All(t *testing.T) {
-	setInput("a\n")
-	result, err := RequestResource()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if !result.AllResources {
-		t.Error("expected AllResources to be true")
+// resourceNames joins the descriptions of a RequestResources result.
+func resourceNames(result *ResourceResult) string {
+	var names []string
+	for _, res := range result.Resources {
+		names = append(names, res.Description)
 	}
+	return strings.Join(names, ",")
 }
 
-func TestRequestResource_Rooms(t *testing.T) {
-	setInput("r\n")
-	result, err := RequestResource()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if result.AllResources {
-		t.Error("expected AllResources to be false")
-	}
-	if result.Single == nil || result.Single.Description != "rooms" {
-		t.Errorf("expected rooms resource, got %+v", result.Single)
+func TestRequestResources_All(t *testing.T) {
+	for _, input := range []string{"a\n", "all\n", "m, all\n"} {
+		setInput(input)
+		result, err := RequestResources()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", input, err)
+		}
+		if !result.AllResources {
+			t.Errorf("%q: expected AllResources to be true", input)
+		}
+		if got := resourceNames(result); got != "rooms,messages,memberships,attachmentActions" {
+			t.Errorf("%q: resources = %s, want every firehose resource", input, got)
+		}
 	}
 }
 
-func TestRequestResource_Messages(t *testing.T) {
-	setInput("m\n")
-	result, err := RequestResource()
+func TestRequestResources_AllWithOptIn(t *testing.T) {
+	setInput("u, all\n")
+	result, err := RequestResources()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.Single == nil || result.Single.Description != "messages" {
-		t.Errorf("expected messages resource, got %+v", result.Single)
+	// Opt-in resources are kept, after the firehose resources
+	if got := resourceNames(result); got != "rooms,messages,memberships,attachmentActions,unclassified" {
+		t.Errorf("resources = %s, want the firehose plus unclassified", got)
 	}
 }
 
-func TestRequestResource_Memberships(t *testing.T) {
-	setInput("mm\n")
-	result, err := RequestResource()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if result.Single == nil || result.Single.Description != "memberships" {
-		t.Errorf("expected memberships resource, got %+v", result.Single)
+func TestRequestResources_Single(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"r\n", "rooms"},
+		{"m\n", "messages"},
+		{"mm\n", "memberships"},
+		{"aa\n", "attachmentActions"},
+		{"messages\n", "messages"},
+		{"AttachmentActions\n", "attachmentActions"},
+		{"tm\n", "teams"},
+		{"re\n", "reactions"},
+		{"rr\n", "readReceipts"},
+		{"t\n", "typing"},
+		{"p\n", "presence"},
+		{"mt\n", "meetings"},
+		{"c\n", "calls"},
+		{"u\n", "unclassified"},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		result, err := RequestResources()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.input, err)
+		}
+		if result.AllResources {
+			t.Errorf("%q: expected AllResources to be false", tt.input)
+		}
+		if got := resourceNames(result); got != tt.want {
+			t.Errorf("%q: resources = %s, want %s", tt.input, got, tt.want)
+		}
 	}
 }
 
-func TestRequestResource_AttachmentActions(t *testing.T) {
-	setInput("aa\n")
-	result, err := RequestResource()
+func TestRequestResources_Multiple(t *testing.T) {
+	setInput("m, memberships ,m,,aa\n")
+	result, err := RequestResources()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.Single == nil || result.Single.Description != "attachmentActions" {
-		t.Errorf("expected attachmentActions resource, got %+v", result.Single)
+	// Order is kept and duplicates are dropped
+	if got := resourceNames(result); got != "messages,memberships,attachmentActions" {
+		t.Errorf("resources = %s, want messages,memberships,attachmentActions", got)
 	}
 }
 
-func TestRequestResource_Invalid(t *testing.T) {
-	setInput("xyz\n")
-	_, err := RequestResource()
+func TestRequestResources_Invalid(t *testing.T) {
+	setInput("m,xyz\n")
+	_, err := RequestResources()
 	if err == nil {
 		t.Fatal("expected error for invalid selection")
 	}
-	if err.Error() != "invalid selection" {
-		t.Errorf("error = %q, want %q", err.Error(), "invalid selection")
+	if err.Error() != `unknown resource "xyz"` {
+		t.Errorf("error = %q, want %q", err.Error(), `unknown resource "xyz"`)
 	}
 }
 
-func TestRequestResource_Empty(t *testing.T) {
-	setInput("\n")
-	_, err := RequestResource()
-	if err == nil {
-		t.Fatal("expected error for empty selection")
+func TestRequestResources_Empty(t *testing.T) {
+	for _, input := range []string{"\n", " , \n"} {
+		setInput(input)
+		if _, err := RequestResources(); err == nil {
+			t.Errorf("%q: expected error for empty selection", input)
+		}
 	}
 }
 
-// ── RequestEvent tests ──────────────────────────────────────────────────
-
-func TestRequestEvent_All(t *testing.T) {
-	setInput("a\n")
-	event, err := RequestEvent([]string{"all", "created", "updated"})
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if event != "all" {
-		t.Errorf("event = %q, want %q", event, "all")
-	}
-}
+// ── RequestEvents tests ─────────────────────────────────────────────────
 
-func TestRequestEvent_Created(t *testing.T) {
-	setInput("c\n")
-	event, err := RequestEvent([]string{"all", "created", "deleted"})
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if event != "created" {
-		t.Errorf("event = %q, want %q", event, "created")
+func TestRequestEvents(t *testing.T) {
+	memberships := config.Resources["memberships"]
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"all\n", "all", ""},
+		{"a\n", "all", ""},
+		{"created\n", "created", ""},
+		{"c\n", "created", ""},
+		{"deleted, Created\n", "deleted,created", ""},
+		{"created,upd,created\n", "created,updated", ""},
+		{"created,all\n", "all", ""},
+		{"z\n", "", `unknown event "z"`},
+		{"\n", "", "response empty"},
+	}
+	for _, tt := range tests {
+		setInput(tt.input)
+		got, err := RequestEvents(memberships)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: error = %v, want it to contain %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, ",") != tt.want {
+			t.Errorf("%q: events = %v, %v; want %s", tt.input, got, err, tt.want)
+		}
 	}
 }
 
-func TestRequestEvent_Invalid(t *testing.T) {
-	setInput("z\n")
-	_, err := RequestEvent([]string{"all", "created"})
-	if err == nil {
-		t.Fatal("expected error for invalid event")
-	}
-	if err.Error() != "event invalid" {
-		t.Errorf("error = %q, want %q", err.Error(), "event invalid")
+func TestMatchEvent_Ambiguous(t *testing.T) {
+	// A future resource with two events sharing a first letter
+	events := []string{"all", "created", "closed"}
+	if _, err := matchEvent(events, "c"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("matchEvent(c) error = %v, want ambiguous", err)
 	}
-}
-
-func TestRequestEvent_Empty(t *testing.T) {
-	setInput("\n")
-	_, err := RequestEvent([]string{"all", "created"})
-	if err == nil {
-		t.Fatal("expected error for empty event")
+	if got, err := matchEvent(events, "cl"); err != nil || got != "closed" {
+		t.Errorf("matchEvent(cl) = %q, %v; want closed", got, err)
 	}
 }
 
@@ -226,7 +303,7 @@ func TestRequestEvent_Empty(t *testing.T) {
 func TestRequestToken_EOF(t *testing.T) {
 	setInput("") // empty = immediate EOF
 	_, err := RequestToken()
-	if err == nil {
-		t.Fatal("expected error on EOF")
+	if err != ErrEOF {
+		t.Fatalf("error = %v, want ErrEOF", err)
 	}
 }
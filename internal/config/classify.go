@@ -0,0 +1,134 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package config
+
+// ResourceUnclassified is the opt-in resource for Mercury activities that
+// no rule in ActivityRules matches. Its only event is EventReceived.
+const (
+	ResourceUnclassified = "unclassified"
+	EventReceived        = "received"
+)
+
+// ActivityFields are the parts of a Mercury conversation activity the
+// classifier looks at.
+type ActivityFields struct {
+	Verb       string // activity verb, e.g. "post"
+	ObjectType string // object.objectType, e.g. "comment"
+	TargetType string // target.objectType, e.g. "conversation"
+	ParentType string // parent.type, e.g. "edit" or "reply"
+
+	// Titled reports whether the object carries a displayName, which on
+	// room updates means the title changed.
+	Titled bool
+}
+
+// ActivityRule maps activities to a resource/event pair. Empty type lists
+// and an empty ParentType match anything; Titled requires a titled object.
+type ActivityRule struct {
+	Verb        string
+	ObjectTypes []string
+	TargetTypes []string
+	ParentType  string
+	Titled      bool
+	Resource    string
+	Event       string
+}
+
+// matches reports whether the rule applies to a.
+func (r ActivityRule) matches(a ActivityFields) bool {
+	return r.Verb == a.Verb &&
+		(len(r.ObjectTypes) == 0 || contains(r.ObjectTypes, a.ObjectType)) &&
+		(len(r.TargetTypes) == 0 || contains(r.TargetTypes, a.TargetType)) &&
+		(r.ParentType == "" || r.ParentType == a.ParentType) &&
+		(!r.Titled || a.Titled)
+}
+
+// Object types seen on message activities. Older clients omit the object
+// type on some message activities, so "" is accepted as well.
+var messageObjectTypes = []string{"comment", "content", ""}
+
+// Object types of emoji reaction activities; "reaction2" is the current
+// one, "reaction" the legacy one.
+var reactionObjectTypes = []string{"reaction2", "reaction"}
+
+// ActivityRules classifies Mercury "conversation.activity" events into the
+// resource/event pairs used by Webex webhooks. Rules are tried in order and
+// the first match wins, so narrower rules come first.
+//
+// Mercury reuses verbs across object types: "update" is sent for room
+// renames as well as team and tag changes, an edited message arrives as a
+// "post" whose parent has type "edit", and reactions use the "add" and
+// "delete" verbs of memberships and message deletes. Matching on the verb
+// alone would mislabel those, so anything not listed here is unclassified.
+var ActivityRules = []ActivityRule{
+	{Verb: "add", ObjectTypes: reactionObjectTypes, Resource: "reactions", Event: "created"},
+	{Verb: "delete", ObjectTypes: reactionObjectTypes, Resource: "reactions", Event: "deleted"},
+	{Verb: "post", ObjectTypes: messageObjectTypes, ParentType: "edit", Resource: "messages", Event: "updated"},
+	{Verb: "share", ObjectTypes: messageObjectTypes, ParentType: "edit", Resource: "messages", Event: "updated"},
+	{Verb: "post", ObjectTypes: messageObjectTypes, Resource: "messages", Event: "created"},
+	{Verb: "share", ObjectTypes: messageObjectTypes, Resource: "messages", Event: "created"},
+	{Verb: "update", ObjectTypes: []string{"comment", "content"}, Resource: "messages", Event: "updated"},
+	{Verb: "delete", ObjectTypes: []string{"activity", "comment", "content", ""}, Resource: "messages", Event: "deleted"},
+	{Verb: "create", ObjectTypes: []string{"team"}, Resource: "teams", Event: "created"},
+	{Verb: "update", ObjectTypes: []string{"team"}, Resource: "teams", Event: "updated"},
+	{Verb: "create", ObjectTypes: []string{"conversation", ""}, Resource: "rooms", Event: "created"},
+	{Verb: "update", ObjectTypes: []string{"conversation"}, Titled: true, Resource: "rooms", Event: "renamed"},
+	{Verb: "update", ObjectTypes: []string{"conversation"}, Resource: "rooms", Event: "updated"},
+	{Verb: "lock", ObjectTypes: []string{"conversation", ""}, Resource: "rooms", Event: "locked"},
+	{Verb: "unlock", ObjectTypes: []string{"conversation", ""}, Resource: "rooms", Event: "unlocked"},
+	{Verb: "add", ObjectTypes: []string{"person", ""}, TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "created"},
+	{Verb: "update", ObjectTypes: []string{"person"}, TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "updated"},
+	{Verb: "leave", ObjectTypes: []string{"person", ""}, TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "deleted"},
+	{Verb: "remove", ObjectTypes: []string{"person", ""}, TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "deleted"},
+	{Verb: "assignModerator", TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "moderatorAssigned"},
+	{Verb: "unassignModerator", TargetTypes: []string{"conversation", ""}, Resource: "memberships", Event: "moderatorUnassigned"},
+	{Verb: "cardAction", Resource: "attachmentActions", Event: "created"},
+	{Verb: "acknowledge", Resource: "readReceipts", Event: "created"},
+}
+
+// MercuryEventTypes maps Mercury event types other than
+// "conversation.activity" to resource/event pairs. These have no webhook
+// equivalent and are only delivered over Mercury.
+var MercuryEventTypes = map[string]struct {
+	Resource string
+	Event    string
+}{
+	"status.start_typing":          {Resource: "typing", Event: "started"},
+	"status.stop_typing":           {Resource: "typing", Event: "stopped"},
+	"apheleia.subscription_update": {Resource: "presence", Event: "updated"},
+	"locus.participant_joined":     {Resource: "meetings", Event: "participantJoined"},
+	"locus.participant_left":       {Resource: "meetings", Event: "participantLeft"},
+}
+
+// MeetingStateEvents maps Locus meeting states to meetings events. Locus
+// has no started or ended event type; these are emitted when a meeting's
+// locus.fullState.state changes between states that map to different
+// events.
+var MeetingStateEvents = map[string]string{
+	"ACTIVE":      "started",
+	"INACTIVE":    "ended",
+	"TERMINATING": "ended",
+}
+
+// Classify returns the resource and event for an activity. Activities that
+// match no rule are reported as unclassified:received.
+func Classify(a ActivityFields) (resource, event string) {
+	for _, r := range ActivityRules {
+		if r.matches(a) {
+			return r.Resource, r.Event
+		}
+	}
+	return ResourceUnclassified, EventReceived
+}
+
+// contains reports whether list holds s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
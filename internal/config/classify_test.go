@@ -0,0 +1,106 @@
+package config
+
+import "testing"
+
+// ── Classify tests ──────────────────────────────────────────────────────
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		activity ActivityFields
+		resource string
+		event    string
+	}{
+		{"message", ActivityFields{Verb: "post", ObjectType: "comment", TargetType: "conversation"}, "messages", "created"},
+		{"file share", ActivityFields{Verb: "share", ObjectType: "content", TargetType: "conversation"}, "messages", "created"},
+		{"thread reply", ActivityFields{Verb: "post", ObjectType: "comment", TargetType: "conversation", ParentType: "reply"}, "messages", "created"},
+		{"message edit", ActivityFields{Verb: "post", ObjectType: "comment", TargetType: "conversation", ParentType: "edit"}, "messages", "updated"},
+		{"share edit", ActivityFields{Verb: "share", ObjectType: "content", ParentType: "edit"}, "messages", "updated"},
+		{"message update", ActivityFields{Verb: "update", ObjectType: "comment"}, "messages", "updated"},
+		{"message delete", ActivityFields{Verb: "delete", ObjectType: "activity", TargetType: "conversation"}, "messages", "deleted"},
+		{"room create", ActivityFields{Verb: "create", ObjectType: "conversation"}, "rooms", "created"},
+		{"room rename", ActivityFields{Verb: "update", ObjectType: "conversation", TargetType: "conversation", Titled: true}, "rooms", "renamed"},
+		{"room update", ActivityFields{Verb: "update", ObjectType: "conversation", TargetType: "conversation"}, "rooms", "updated"},
+		{"room lock", ActivityFields{Verb: "lock", ObjectType: "conversation"}, "rooms", "locked"},
+		{"room unlock", ActivityFields{Verb: "unlock", ObjectType: "conversation"}, "rooms", "unlocked"},
+		{"team create", ActivityFields{Verb: "create", ObjectType: "team"}, "teams", "created"},
+		{"team update", ActivityFields{Verb: "update", ObjectType: "team", Titled: true}, "teams", "updated"},
+		{"member update", ActivityFields{Verb: "update", ObjectType: "person", TargetType: "conversation"}, "memberships", "updated"},
+		{"member add", ActivityFields{Verb: "add", ObjectType: "person", TargetType: "conversation"}, "memberships", "created"},
+		{"member leave", ActivityFields{Verb: "leave", ObjectType: "person", TargetType: "conversation"}, "memberships", "deleted"},
+		{"member remove", ActivityFields{Verb: "remove", ObjectType: "person", TargetType: "conversation"}, "memberships", "deleted"},
+		{"moderator", ActivityFields{Verb: "assignModerator", ObjectType: "person", TargetType: "conversation"}, "memberships", "moderatorAssigned"},
+		{"unmoderator", ActivityFields{Verb: "unassignModerator", ObjectType: "person", TargetType: "conversation"}, "memberships", "moderatorUnassigned"},
+		{"reaction", ActivityFields{Verb: "add", ObjectType: "reaction2", TargetType: "activity", ParentType: "reaction"}, "reactions", "created"},
+		{"legacy reaction", ActivityFields{Verb: "add", ObjectType: "reaction"}, "reactions", "created"},
+		{"reaction removed", ActivityFields{Verb: "delete", ObjectType: "reaction2", ParentType: "reaction"}, "reactions", "deleted"},
+		{"read receipt", ActivityFields{Verb: "acknowledge", ObjectType: "activity", TargetType: "conversation"}, "readReceipts", "created"},
+		{"card action", ActivityFields{Verb: "cardAction", ObjectType: "submit", TargetType: "conversation"}, "attachmentActions", "created"},
+
+		// Verbs shared with other object types must not be mislabeled
+		{"team member add", ActivityFields{Verb: "add", ObjectType: "person", TargetType: "team"}, ResourceUnclassified, EventReceived},
+		{"space tag", ActivityFields{Verb: "tag", ObjectType: "conversation"}, ResourceUnclassified, EventReceived},
+		{"unknown verb", ActivityFields{Verb: "unknownVerb"}, ResourceUnclassified, EventReceived},
+	}
+	for _, tt := range tests {
+		resource, event := Classify(tt.activity)
+		if resource != tt.resource || event != tt.event {
+			t.Errorf("%s: Classify(%+v) = %s:%s, want %s:%s", tt.name, tt.activity, resource, event, tt.resource, tt.event)
+		}
+	}
+}
+
+func TestActivityRulesUseKnownEvents(t *testing.T) {
+	for _, r := range ActivityRules {
+		res, ok := Resources[r.Resource]
+		if !ok {
+			t.Errorf("rule for %s maps to unknown resource %q", r.Verb, r.Resource)
+			continue
+		}
+		if r.Event == "all" || !resourceHasEvent(res, r.Event) {
+			t.Errorf("rule for %s maps to %s:%s, which is not a resource event", r.Verb, r.Resource, r.Event)
+		}
+	}
+}
+
+func TestActivityRulesCoverAllResources(t *testing.T) {
+	// Every resource event should be produced by a rule or event type
+	covered := make(map[string]bool)
+	for _, r := range ActivityRules {
+		covered[r.Resource+":"+r.Event] = true
+	}
+	for _, m := range MercuryEventTypes {
+		covered[m.Resource+":"+m.Event] = true
+	}
+	for _, ev := range MeetingStateEvents {
+		covered["meetings:"+ev] = true
+	}
+	for name, res := range Resources {
+		// Calls events come from the listener's calling line, not Mercury
+		if name == ResourceUnclassified || name == "calls" {
+			continue
+		}
+		for _, ev := range res.Events {
+			if ev != "all" && !covered[name+":"+ev] {
+				t.Errorf("no rule produces %s:%s", name, ev)
+			}
+		}
+	}
+}
+
+func TestMercuryEventTypesUseKnownEvents(t *testing.T) {
+	for eventType, m := range MercuryEventTypes {
+		res, ok := Resources[m.Resource]
+		if !ok || m.Event == "all" || !resourceHasEvent(res, m.Event) {
+			t.Errorf("%s maps to %s:%s, which is not a resource event", eventType, m.Resource, m.Event)
+		}
+	}
+}
+
+func TestMeetingStateEventsUseKnownEvents(t *testing.T) {
+	for state, ev := range MeetingStateEvents {
+		if !resourceHasEvent(Resources["meetings"], ev) || ev == "all" {
+			t.Errorf("state %s maps to meetings:%s, which is not a resource event", state, ev)
+		}
+	}
+}
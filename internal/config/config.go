@@ -5,8 +5,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,10 +23,39 @@ type Resource struct {
 	Events      []string // Supported event types including "all"
 }
 
-// Selection holds the user's chosen resource and event.
-type Selection struct {
+// Subscription is one resource and the events selected for it. Events
+// holds specific event names, or just "all".
+type Subscription struct {
 	Resource string
-	Event    string
+	Events   []string
+}
+
+// Selection is the set of resource/event subscriptions for a session or
+// pipeline, in the order they were chosen.
+type Selection []Subscription
+
+// FirehoseSelection subscribes to every event of every firehose resource.
+func FirehoseSelection() Selection {
+	sel := make(Selection, len(FirehoseResourceNames))
+	for i, name := range FirehoseResourceNames {
+		sel[i] = Subscription{Resource: name, Events: []string{"all"}}
+	}
+	return sel
+}
+
+// Matches reports whether the selection includes resource:event.
+func (s Selection) Matches(resource, event string) bool {
+	for _, sub := range s {
+		if sub.Resource != resource {
+			continue
+		}
+		for _, ev := range sub.Events {
+			if ev == "all" || ev == event {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // Specs holds the complete configuration for a hookbuster session.
@@ -43,29 +77,73 @@ type WebhookEvent struct {
 // FirehoseResourceNames lists all resource names for "all" (firehose) mode.
 var FirehoseResourceNames = []string{"rooms", "messages", "memberships", "attachmentActions"}
 
+// OptInResourceNames lists resources that are only subscribed when named
+// explicitly; "all" and the firehose leave them out.
+var OptInResourceNames = []string{"teams", "reactions", "readReceipts", "typing", "presence", "meetings", "calls", ResourceUnclassified}
+
 // Resources maps resource names to their definitions.
 // This mirrors the Node.js hookbuster's cli.js options object.
 var Resources = map[string]*Resource{
 	"rooms": {
 		Alias:       "r",
 		Description: "rooms",
-		Events:      []string{"all", "created", "updated"},
+		Events:      []string{"all", "created", "updated", "renamed", "locked", "unlocked"},
 	},
 	"messages": {
 		Alias:       "m",
 		Description: "messages",
-		Events:      []string{"all", "created", "deleted"},
+		Events:      []string{"all", "created", "updated", "deleted"},
 	},
 	"memberships": {
 		Alias:       "mm",
 		Description: "memberships",
-		Events:      []string{"all", "created", "updated", "deleted"},
+		Events:      []string{"all", "created", "updated", "deleted", "moderatorAssigned", "moderatorUnassigned"},
 	},
 	"attachmentActions": {
 		Alias:       "aa",
 		Description: "attachmentActions",
 		Events:      []string{"created"},
 	},
+	"teams": {
+		Alias:       "tm",
+		Description: "teams",
+		Events:      []string{"all", "created", "updated"},
+	},
+	"reactions": {
+		Alias:       "re",
+		Description: "reactions",
+		Events:      []string{"all", "created", "deleted"},
+	},
+	"readReceipts": {
+		Alias:       "rr",
+		Description: "readReceipts",
+		Events:      []string{"created"},
+	},
+	"typing": {
+		Alias:       "t",
+		Description: "typing",
+		Events:      []string{"all", "started", "stopped"},
+	},
+	"presence": {
+		Alias:       "p",
+		Description: "presence",
+		Events:      []string{"updated"},
+	},
+	"meetings": {
+		Alias:       "mt",
+		Description: "meetings",
+		Events:      []string{"all", "started", "ended", "participantJoined", "participantLeft"},
+	},
+	"calls": {
+		Alias:       "c",
+		Description: "calls",
+		Events:      []string{"all", "incoming", "answered", "disconnected"},
+	},
+	ResourceUnclassified: {
+		Alias:       "u",
+		Description: ResourceUnclassified,
+		Events:      []string{EventReceived},
+	},
 }
 
 // ── Multi-pipeline configuration types ──────────────────────────────────
@@ -83,93 +161,708 @@ var ValidModes = map[string]bool{
 }
 
 // Target represents a single webhook forwarding destination.
+//
+// The URL scheme selects the target kind: "http"/"https" POST each event
+// as JSON, "kafka" publishes to a topic (kafka://broker1:9092,broker2:9092/topic),
+// "nats" publishes to a subject per event (nats://host:4222), "redis"/"rediss"
+// appends to a stream or publishes to a channel (redis://host:6379/0),
+// "amqp"/"amqps" publishes to a RabbitMQ exchange (amqp://host:5672/vhost),
+// "grpc"/"grpcs" streams to a receiver implementing the hookbuster.v1
+// EventStream service (grpc://host:50051), "file" appends JSON Lines to a
+// rotating file (file:///var/log/hookbuster/events.jsonl), "stdout" prints
+// one compact JSON line per event (stdout://) and "exec" pipes each event to
+// a command's stdin (exec:///usr/local/bin/handler).
+// Kind-specific settings live in the matching options block.
 type Target struct {
-	URL string `yaml:"url" json:"url"`
+	URL   string        `yaml:"url"             json:"url"`
+	Kafka *KafkaOptions `yaml:"kafka,omitempty" json:"kafka,omitempty"`
+	NATS  *NATSOptions  `yaml:"nats,omitempty"  json:"nats,omitempty"`
+	Redis *RedisOptions `yaml:"redis,omitempty" json:"redis,omitempty"`
+	AMQP  *AMQPOptions  `yaml:"amqp,omitempty"  json:"amqp,omitempty"`
+	GRPC  *GRPCOptions  `yaml:"grpc,omitempty"  json:"grpc,omitempty"`
+	File  *FileOptions  `yaml:"file,omitempty"  json:"file,omitempty"`
+	Exec  *ExecOptions  `yaml:"exec,omitempty"  json:"exec,omitempty"`
+}
+
+// SecretEnvs returns the env var names the target's options read secrets
+// from (SASL and broker passwords, tokens), in option order.
+func (t Target) SecretEnvs() []string {
+	var envs []string
+	add := func(name string) {
+		if name != "" {
+			envs = append(envs, name)
+		}
+	}
+	if t.Kafka != nil && t.Kafka.SASL != nil {
+		add(t.Kafka.SASL.PasswordEnv)
+	}
+	if t.NATS != nil {
+		add(t.NATS.TokenEnv)
+		add(t.NATS.PasswordEnv)
+	}
+	if t.Redis != nil {
+		add(t.Redis.PasswordEnv)
+	}
+	if t.AMQP != nil {
+		add(t.AMQP.PasswordEnv)
+	}
+	if t.GRPC != nil {
+		add(t.GRPC.TokenEnv)
+	}
+	return envs
+}
+
+// Kafka producer acknowledgement levels.
+const (
+	KafkaAcksNone   = "none"
+	KafkaAcksLeader = "leader"
+	KafkaAcksAll    = "all"
+)
+
+// ValidKafkaAcks lists all accepted values for kafka.acks.
+var ValidKafkaAcks = map[string]bool{
+	KafkaAcksNone:   true,
+	KafkaAcksLeader: true,
+	KafkaAcksAll:    true,
+}
+
+// ValidKafkaCompression lists all accepted values for kafka.compression.
+var ValidKafkaCompression = map[string]bool{
+	"none":   true,
+	"gzip":   true,
+	"snappy": true,
+	"lz4":    true,
+	"zstd":   true,
+}
+
+// SASL mechanisms supported by broker-style targets.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// ValidSASLMechanisms lists all accepted values for sasl.mechanism.
+var ValidSASLMechanisms = map[string]bool{
+	SASLPlain:       true,
+	SASLScramSHA256: true,
+	SASLScramSHA512: true,
+}
+
+// KafkaOptions configures a kafka:// target.
+type KafkaOptions struct {
+	Acks        string       `yaml:"acks"        json:"acks,omitempty"`        // none, leader, all (default)
+	Compression string       `yaml:"compression" json:"compression,omitempty"` // none (default), gzip, snappy, lz4, zstd
+	TLS         *TLSOptions  `yaml:"tls"         json:"tls,omitempty"`
+	SASL        *SASLOptions `yaml:"sasl"        json:"sasl,omitempty"`
+}
+
+// DefaultNATSSubjectPrefix is the first subject token for nats:// targets.
+const DefaultNATSSubjectPrefix = "webex"
+
+// NATSOptions configures a nats:// target. Events are published to
+// "<subject_prefix>.<pipeline>.<resource>.<event>".
+type NATSOptions struct {
+	SubjectPrefix string      `yaml:"subject_prefix" json:"subject_prefix,omitempty"` // default "webex"
+	JetStream     bool        `yaml:"jetstream"      json:"jetstream,omitempty"`      // wait for JetStream publish acks
+	CredsFile     string      `yaml:"creds_file"     json:"creds_file,omitempty"`
+	TokenEnv      string      `yaml:"token_env"      json:"token_env,omitempty"`
+	Username      string      `yaml:"username"       json:"username,omitempty"`
+	PasswordEnv   string      `yaml:"password_env"   json:"password_env,omitempty"`
+	TLS           *TLSOptions `yaml:"tls"            json:"tls,omitempty"`
+}
+
+// Redis target delivery modes.
+const (
+	RedisModeStream = "stream"
+	RedisModePubSub = "pubsub"
+)
+
+// DefaultRedisKey is the stream/channel name template for redis:// targets.
+const DefaultRedisKey = "webex:{pipeline}:{resource}:{event}"
+
+// RedisOptions configures a redis:// target. Key is a template where
+// {pipeline}, {resource} and {event} are substituted per event.
+type RedisOptions struct {
+	Mode        string      `yaml:"mode"         json:"mode,omitempty"`   // stream (default), pubsub
+	Key         string      `yaml:"key"          json:"key,omitempty"`    // default DefaultRedisKey
+	MaxLen      int64       `yaml:"maxlen"       json:"maxlen,omitempty"` // approximate XADD MAXLEN; 0 keeps everything
+	Username    string      `yaml:"username"     json:"username,omitempty"`
+	PasswordEnv string      `yaml:"password_env" json:"password_env,omitempty"`
+	TLS         *TLSOptions `yaml:"tls"          json:"tls,omitempty"`
+}
+
+// Defaults for amqp:// targets. amq.topic exists on every RabbitMQ broker,
+// so events can be bound to queues without declaring an exchange first.
+const (
+	DefaultAMQPExchange   = "amq.topic"
+	DefaultAMQPRoutingKey = "{resource}.{event}"
+)
+
+// AMQPOptions configures an amqp:// target. RoutingKey is a template where
+// {pipeline}, {resource} and {event} are substituted per event.
+type AMQPOptions struct {
+	Exchange    string      `yaml:"exchange"     json:"exchange,omitempty"`    // default "amq.topic"
+	RoutingKey  string      `yaml:"routing_key"  json:"routing_key,omitempty"` // default "{resource}.{event}"
+	Mandatory   *bool       `yaml:"mandatory"    json:"mandatory,omitempty"`   // fail when no queue is bound (default true)
+	Username    string      `yaml:"username"     json:"username,omitempty"`
+	PasswordEnv string      `yaml:"password_env" json:"password_env,omitempty"`
+	TLS         *TLSOptions `yaml:"tls"          json:"tls,omitempty"`
+}
+
+// GRPCOptions configures a grpc:// or grpcs:// target. TLS is only used
+// with grpcs://, where it customises the default system-root TLS config.
+type GRPCOptions struct {
+	TokenEnv string      `yaml:"token_env" json:"token_env,omitempty"` // sent as "authorization: Bearer <token>"
+	TLS      *TLSOptions `yaml:"tls"       json:"tls,omitempty"`
+}
+
+// FileOptions configures rotation for a file:// target. Rotated files are
+// renamed with a timestamp suffix and gzipped unless compress is false.
+type FileOptions struct {
+	MaxSizeMB   int    `yaml:"max_size_mb"  json:"max_size_mb,omitempty"`  // rotate once the file would exceed this size; 0 disables
+	RotateEvery string `yaml:"rotate_every" json:"rotate_every,omitempty"` // rotate after this duration, e.g. "24h"; empty disables
+	MaxBackups  int    `yaml:"max_backups"  json:"max_backups,omitempty"`  // rotated files to keep; 0 keeps all
+	Compress    *bool  `yaml:"compress"     json:"compress,omitempty"`     // gzip rotated files (default true)
+}
+
+// DefaultExecTimeout bounds a single exec:// command run.
+const DefaultExecTimeout = "10s"
+
+// ExecOptions configures an exec:// target. The command runs once per
+// event with the event JSON on stdin; a non-zero exit is a failed delivery.
+type ExecOptions struct {
+	Args    []string `yaml:"args"    json:"args,omitempty"`
+	Timeout string   `yaml:"timeout" json:"timeout,omitempty"` // default "10s"
+}
+
+// TLSOptions configures TLS for targets that hold their own connections.
+type TLSOptions struct {
+	Enabled            bool   `yaml:"enabled"              json:"enabled"`
+	CAFile             string `yaml:"ca_file"              json:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file"            json:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file"             json:"key_file,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify,omitempty"`
+}
+
+// Attachment modes: inline puts the file in the event as base64, dir
+// writes it to a local directory and s3 uploads it to an S3-compatible
+// bucket.
+const (
+	AttachmentsInline = "inline"
+	AttachmentsDir    = "dir"
+	AttachmentsS3     = "s3"
+)
+
+// ValidAttachmentModes lists all accepted values for attachments.mode.
+var ValidAttachmentModes = map[string]bool{
+	AttachmentsInline: true,
+	AttachmentsDir:    true,
+	AttachmentsS3:     true,
+}
+
+// Defaults for pipeline attachments.
+const (
+	DefaultAttachmentMaxSizeMB = 10
+	DefaultS3Region            = "us-east-1"
+)
+
+// AttachmentOptions makes a pipeline download the files shared in messages
+// and replace data.files in their events with the inlined or stored copies.
+// Files over max_size_mb are left out with an error instead.
+type AttachmentOptions struct {
+	Mode      string     `yaml:"mode"        json:"mode"`
+	MaxSizeMB int        `yaml:"max_size_mb" json:"max_size_mb,omitempty"` // default 10
+	Dir       string     `yaml:"dir"         json:"dir,omitempty"`         // dir mode: directory to write files to
+	S3        *S3Options `yaml:"s3"          json:"s3,omitempty"`
+}
+
+// SecretEnvs returns the env var names the attachment options read secrets
+// from.
+func (a *AttachmentOptions) SecretEnvs() []string {
+	if a == nil || a.S3 == nil {
+		return nil
+	}
+	var envs []string
+	for _, name := range []string{a.S3.AccessKeyEnv, a.S3.SecretKeyEnv} {
+		if name != "" {
+			envs = append(envs, name)
+		}
+	}
+	return envs
+}
+
+// S3Options configures uploads to an S3-compatible endpoint such as AWS S3
+// or MinIO. Objects are addressed path-style (<endpoint>/<bucket>/<key>)
+// and keyed "<prefix><message id>/<n>-<file name>".
+type S3Options struct {
+	Endpoint     string `yaml:"endpoint"       json:"endpoint"` // e.g. https://s3.eu-west-1.amazonaws.com
+	Bucket       string `yaml:"bucket"         json:"bucket"`
+	Region       string `yaml:"region"         json:"region,omitempty"` // default "us-east-1"
+	Prefix       string `yaml:"prefix"         json:"prefix,omitempty"`
+	AccessKeyEnv string `yaml:"access_key_env" json:"access_key_env"`
+	SecretKeyEnv string `yaml:"secret_key_env" json:"secret_key_env"`
+	PublicURL    string `yaml:"public_url"     json:"public_url,omitempty"` // base of rewritten urls; default <endpoint>/<bucket>
+}
+
+// SASLOptions configures SASL authentication. Like Webex tokens, the
+// password is referenced by env var name and never stored in the file.
+type SASLOptions struct {
+	Mechanism   string `yaml:"mechanism"    json:"mechanism"`
+	Username    string `yaml:"username"     json:"username"`
+	PasswordEnv string `yaml:"password_env" json:"password_env"`
 }
 
 // Pipeline represents a single token-to-targets mapping.
 type Pipeline struct {
-	Name      string   `yaml:"name"      json:"name"`
-	TokenEnv  string   `yaml:"token_env" json:"token_env"`
-	Mode      string   `yaml:"mode"      json:"mode"`
-	Resources []string `yaml:"resources" json:"resources"`
-	Events    string   `yaml:"events"    json:"events"`
-	Targets   []Target `yaml:"targets"   json:"targets"`
+	Name      string      `yaml:"name"                json:"name"`
+	TokenEnv  string      `yaml:"token_env"           json:"token_env"`
+	Mode      string      `yaml:"mode,omitempty"      json:"mode"`
+	Resources []string    `yaml:"resources,omitempty" json:"resources"`
+	Events    EventFilter `yaml:"events,omitempty"    json:"events"`
+	Targets   []Target    `yaml:"targets,omitempty"   json:"targets"`
+
+	Attachments *AttachmentOptions `yaml:"attachments,omitempty" json:"attachments,omitempty"`
+
+	// IgnoreSelf drops activities performed by the token's own identity,
+	// such as a bot's replies. IgnoreActors drops activities of other
+	// actors, given as person IDs or email addresses.
+	IgnoreSelf   bool     `yaml:"ignore_self,omitempty"   json:"ignore_self,omitempty"`
+	IgnoreActors []string `yaml:"ignore_actors,omitempty" json:"ignore_actors,omitempty"`
+
+	// IDs selects the form of forwarded identifiers: uuid (default), hydra
+	// or both.
+	IDs string `yaml:"ids,omitempty" json:"ids,omitempty"`
+
+	// Enrich adds room details (roomTitle, roomType, isDirect, teamId) and
+	// missing actor details to events, from the pipeline's own metadata
+	// cache.
+	Enrich bool `yaml:"enrich,omitempty" json:"enrich,omitempty"`
+}
+
+// Forms of forwarded identifiers. uuid forwards the UUIDs Mercury uses,
+// hydra replaces them with the base64 IDs of the REST API, and both uses
+// REST IDs and keeps each UUID in a "<name>Uuid" field.
+const (
+	IDsUUID  = "uuid"
+	IDsHydra = "hydra"
+	IDsBoth  = "both"
+)
+
+// ValidIDForms lists all accepted values for the pipeline ids field.
+var ValidIDForms = map[string]bool{
+	IDsUUID:  true,
+	IDsHydra: true,
+	IDsBoth:  true,
+}
+
+// Selection returns the pipeline's subscriptions. No resources means the
+// resources named in per-resource events, or else the firehose resources;
+// resources without an event filter get "all".
+func (p Pipeline) Selection() Selection {
+	resources := p.Resources
+	if len(resources) == 0 {
+		for _, sub := range p.Events.PerResource {
+			resources = append(resources, sub.Resource)
+		}
+	}
+	if len(resources) == 0 {
+		resources = FirehoseResourceNames
+	}
+
+	sel := make(Selection, len(resources))
+	for i, r := range resources {
+		sel[i] = Subscription{Resource: r, Events: p.Events.eventsFor(r)}
+	}
+	return sel
+}
+
+// SetSelection stores sel in the pipeline's resources and events. A
+// selection with the same single event for every resource uses the
+// single-event form; anything else is stored per resource.
+func (p *Pipeline) SetSelection(sel Selection) {
+	uniform := len(sel) > 0
+	resources := make([]string, len(sel))
+	for i, sub := range sel {
+		resources[i] = sub.Resource
+		if len(sub.Events) != 1 || sub.Events[0] != sel[0].Events[0] {
+			uniform = false
+		}
+	}
+
+	if uniform {
+		p.Resources = resources
+		p.Events = EventFilter{Event: sel[0].Events[0]}
+		return
+	}
+	p.Resources = nil
+	p.Events = EventFilter{PerResource: sel}
 }
 
 // HookbusterConfig is the top-level YAML configuration for multi-pipeline mode.
 type HookbusterConfig struct {
-	Pipelines []Pipeline `yaml:"pipelines" json:"pipelines"`
+	Pipelines []Pipeline     `yaml:"pipelines"        json:"pipelines"`
+	Server    *ServerOptions `yaml:"server,omitempty" json:"server,omitempty"`
+	Cache     *CacheOptions  `yaml:"cache,omitempty"  json:"cache,omitempty"`
+
+	// Warnings holds non-fatal problems found by the last validation.
+	Warnings []Problem `yaml:"-" json:"-"`
+}
+
+// Defaults for the local event stream server.
+const (
+	DefaultServerListen = "127.0.0.1:9090"
+	DefaultServerBuffer = 256
+)
+
+// ServerOptions enables the local WebSocket / Server-Sent Events server.
+// Clients connect to /events and receive every event from all pipelines,
+// filtered by query parameters. When the server is enabled, pipelines may
+// omit targets and only stream to connected clients.
+//
+// Browser requests from other origins are refused unless listed in
+// AllowedOrigins. TokenEnvs may only be left empty when the server listens
+// on a loopback address.
+type ServerOptions struct {
+	Listen         string   `yaml:"listen"          json:"listen,omitempty"`          // default "127.0.0.1:9090"
+	TokenEnvs      []string `yaml:"token_envs"      json:"token_envs,omitempty"`      // env vars holding accepted client tokens; none disables auth on loopback
+	AllowedOrigins []string `yaml:"allowed_origins" json:"allowed_origins,omitempty"` // browser origins besides the server's own, e.g. "http://localhost:3000"
+	Buffer         int      `yaml:"buffer"          json:"buffer,omitempty"`          // events queued per client before it is disconnected (default 256)
+}
+
+// IsLoopbackListen reports whether a listen address only accepts local
+// connections: "localhost" or a loopback IP. An empty host listens on
+// every interface.
+func IsLoopbackListen(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Defaults for the metadata cache.
+const (
+	DefaultCacheTTL        = "10m"
+	DefaultCacheMaxEntries = 10000
+)
+
+// CacheOptions tunes the room and person metadata cache each pipeline
+// with enrich set keeps. Entries are refetched after ttl, or sooner
+// when a room or membership event shows they changed.
+type CacheOptions struct {
+	TTL        string `yaml:"ttl"         json:"ttl,omitempty"`         // default "10m"
+	MaxEntries int    `yaml:"max_entries" json:"max_entries,omitempty"` // rooms, and people, kept (default 10000 each)
 }
 
-// LoadConfig reads and validates a YAML configuration file.
+// LoadConfig reads and validates a YAML configuration file. Validation
+// reports every problem at once, as a *ValidationError with line numbers;
+// warnings are left in the returned config's Warnings.
 func LoadConfig(path string) (*HookbusterConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg HookbusterConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	var cfg HookbusterConfig
+	if root.Kind != 0 {
+		if err := root.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := cfg.validate(&root); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks the config the same way LoadConfig does, without line
+// numbers.
+func (c *HookbusterConfig) Validate() error {
+	return c.validate(nil)
+}
+
+// savedConfigHeader is written at the top of files created by SaveConfig.
+const savedConfigHeader = `# Generated by hookbuster. Tokens are never stored here: set the env var
+# named by each pipeline's token_env before running "hookbuster run -c".
+`
+
+// SaveConfig validates cfg and writes it to path as YAML.
+func SaveConfig(path string, cfg *HookbusterConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(savedConfigHeader)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// TargetSchemes lists the URL schemes a target may use.
+var TargetSchemes = []string{"http", "https", "kafka", "nats", "redis", "rediss", "amqp", "amqps", "grpc", "grpcs", "file", "stdout", "exec"}
+
+// ValidateTarget checks a target's URL scheme and host and the
+// kind-specific parts of the target.
+func ValidateTarget(t Target) error {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme == "" {
+		return fmt.Errorf("url has no scheme (e.g. http://host/path)")
+	}
+	known := false
+	for _, s := range TargetSchemes {
+		known = known || s == u.Scheme
+	}
+	if !known {
+		return fmt.Errorf("unsupported scheme %q (valid: %s)", u.Scheme, strings.Join(TargetSchemes, ", "))
+	}
 
-	if len(cfg.Pipelines) == 0 {
-		return nil, fmt.Errorf("config must contain at least one pipeline")
+	if t.Kafka != nil && u.Scheme != "kafka" {
+		return fmt.Errorf("kafka options require a kafka:// url")
+	}
+	if t.NATS != nil && u.Scheme != "nats" {
+		return fmt.Errorf("nats options require a nats:// url")
+	}
+	if t.Redis != nil && u.Scheme != "redis" && u.Scheme != "rediss" {
+		return fmt.Errorf("redis options require a redis:// or rediss:// url")
+	}
+	if t.AMQP != nil && u.Scheme != "amqp" && u.Scheme != "amqps" {
+		return fmt.Errorf("amqp options require an amqp:// or amqps:// url")
+	}
+	if t.GRPC != nil && u.Scheme != "grpc" && u.Scheme != "grpcs" {
+		return fmt.Errorf("grpc options require a grpc:// or grpcs:// url")
+	}
+	if t.File != nil && u.Scheme != "file" {
+		return fmt.Errorf("file options require a file:// url")
+	}
+	if t.Exec != nil && u.Scheme != "exec" {
+		return fmt.Errorf("exec options require an exec:// url")
 	}
 
-	for i, p := range cfg.Pipelines {
-		if err := validatePipeline(i, p); err != nil {
-			return nil, err
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("%s url must include a host", u.Scheme)
+		}
+	case "kafka":
+		return validateKafkaTarget(u, t.Kafka)
+	case "nats":
+		return validateNATSTarget(u, t.NATS)
+	case "redis", "rediss":
+		return validateRedisTarget(u, t.Redis)
+	case "amqp", "amqps":
+		return validateAMQPTarget(u, t.AMQP)
+	case "grpc", "grpcs":
+		return validateGRPCTarget(u, t.GRPC)
+	case "file":
+		return validateFileTarget(u, t.File)
+	case "stdout":
+		if u.Host != "" || strings.Trim(u.Path, "/") != "" {
+			return fmt.Errorf("stdout url takes no path; use stdout://")
 		}
+	case "exec":
+		return validateExecTarget(u, t.Exec)
 	}
+	return nil
+}
 
-	return &cfg, nil
+// validateKafkaTarget checks a kafka://brokers/topic url and its options.
+func validateKafkaTarget(u *url.URL, opts *KafkaOptions) error {
+	if u.Host == "" {
+		return fmt.Errorf("kafka url must list at least one broker")
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("kafka url must include a topic (kafka://broker/topic)")
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.Acks != "" && !ValidKafkaAcks[opts.Acks] {
+		return fmt.Errorf("unknown kafka acks %q (valid: %s, %s, %s)", opts.Acks, KafkaAcksNone, KafkaAcksLeader, KafkaAcksAll)
+	}
+	if opts.Compression != "" && !ValidKafkaCompression[opts.Compression] {
+		return fmt.Errorf("unknown kafka compression %q", opts.Compression)
+	}
+	return validateSASL(opts.SASL)
 }
 
-// validatePipeline checks a single pipeline for required fields and valid values.
-func validatePipeline(index int, p Pipeline) error {
-	if p.TokenEnv == "" {
-		return fmt.Errorf("pipeline %d (%q): token_env is required", index, p.Name)
+// validateNATSTarget checks a nats://host url and its options.
+func validateNATSTarget(u *url.URL, opts *NATSOptions) error {
+	if u.Host == "" {
+		return fmt.Errorf("nats url must include a server host")
+	}
+	if opts == nil {
+		return nil
+	}
+	if strings.ContainsAny(opts.SubjectPrefix, " *>") {
+		return fmt.Errorf("nats subject_prefix %q must not contain spaces or wildcards", opts.SubjectPrefix)
+	}
+	if opts.TokenEnv != "" && opts.Username != "" {
+		return fmt.Errorf("nats token_env and username are mutually exclusive")
+	}
+	if opts.Username != "" && opts.PasswordEnv == "" {
+		return fmt.Errorf("nats password_env is required with username")
 	}
-	if len(p.Targets) == 0 {
-		return fmt.Errorf("pipeline %d (%q): at least one target is required", index, p.Name)
+	return nil
+}
+
+// validateRedisTarget checks a redis://host/db url and its options.
+func validateRedisTarget(u *url.URL, opts *RedisOptions) error {
+	if u.Host == "" {
+		return fmt.Errorf("redis url must include a server host")
 	}
-	for _, t := range p.Targets {
-		if t.URL == "" {
-			return fmt.Errorf("pipeline %d (%q): target url must not be empty", index, p.Name)
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return fmt.Errorf("redis url must not embed a password; use redis.password_env")
 		}
 	}
-	for _, r := range p.Resources {
-		if _, ok := Resources[r]; !ok {
-			return fmt.Errorf("pipeline %d (%q): unknown resource %q", index, p.Name, r)
+	if opts == nil {
+		return nil
+	}
+	if opts.Mode != "" && opts.Mode != RedisModeStream && opts.Mode != RedisModePubSub {
+		return fmt.Errorf("unknown redis mode %q (valid: %s, %s)", opts.Mode, RedisModeStream, RedisModePubSub)
+	}
+	if opts.MaxLen < 0 {
+		return fmt.Errorf("redis maxlen must not be negative")
+	}
+	if opts.MaxLen > 0 && opts.Mode == RedisModePubSub {
+		return fmt.Errorf("redis maxlen only applies to stream mode")
+	}
+	return nil
+}
+
+// validateAMQPTarget checks an amqp://host/vhost url and its options.
+func validateAMQPTarget(u *url.URL, opts *AMQPOptions) error {
+	if u.Host == "" {
+		return fmt.Errorf("amqp url must include a broker host")
+	}
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return fmt.Errorf("amqp url must not embed a password; use amqp.password_env")
 		}
 	}
-	if p.Mode != "" && !ValidModes[p.Mode] {
-		return fmt.Errorf("pipeline %d (%q): unknown mode %q (valid: %s, %s)", index, p.Name, p.Mode, ModeFanout, ModeRoundRobin)
+	if opts == nil {
+		return nil
+	}
+	if opts.Username != "" && opts.PasswordEnv == "" {
+		return fmt.Errorf("amqp password_env is required with username")
+	}
+	if strings.ContainsAny(opts.RoutingKey, "*#") {
+		return fmt.Errorf("amqp routing_key %q must not contain wildcards", opts.RoutingKey)
 	}
 	return nil
 }
 
-// VerbToResourceEvent maps Mercury activity verbs (and object types where
-// needed) to hookbuster resource/event pairs.
-//
-// Mercury delivers all real-time events as "conversation.activity" with a
-// verb field.  This table lets the listener translate those into the
-// resource + event pairs that the original Node.js hookbuster uses.
-var VerbToResourceEvent = map[string]struct {
-	Resource string
-	Event    string
-}{
-	"post":              {Resource: "messages", Event: "created"},
-	"share":             {Resource: "messages", Event: "created"},
-	"delete":            {Resource: "messages", Event: "deleted"},
-	"create":            {Resource: "rooms", Event: "created"},
-	"update":            {Resource: "rooms", Event: "updated"},
-	"add":               {Resource: "memberships", Event: "created"},
-	"leave":             {Resource: "memberships", Event: "deleted"},
-	"remove":            {Resource: "memberships", Event: "deleted"},
-	"assignModerator":   {Resource: "memberships", Event: "updated"},
-	"unassignModerator": {Resource: "memberships", Event: "updated"},
-	"cardAction":        {Resource: "attachmentActions", Event: "created"},
+// validateGRPCTarget checks a grpc://host:port url and its options.
+func validateGRPCTarget(u *url.URL, opts *GRPCOptions) error {
+	if u.Host == "" || u.Port() == "" {
+		return fmt.Errorf("grpc url must be %s://host:port", u.Scheme)
+	}
+	if strings.Trim(u.Path, "/") != "" {
+		return fmt.Errorf("grpc url must not include a path")
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.TLS != nil && opts.TLS.Enabled && u.Scheme != "grpcs" {
+		return fmt.Errorf("grpc tls requires a grpcs:// url")
+	}
+	return nil
+}
+
+// validateFileTarget checks a file:// url and its rotation options.
+func validateFileTarget(u *url.URL, opts *FileOptions) error {
+	if TargetPath(u) == "" {
+		return fmt.Errorf("file url must include a path (file:///var/log/events.jsonl)")
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.MaxSizeMB < 0 {
+		return fmt.Errorf("file max_size_mb must not be negative")
+	}
+	if opts.MaxBackups < 0 {
+		return fmt.Errorf("file max_backups must not be negative")
+	}
+	if opts.RotateEvery != "" {
+		d, err := time.ParseDuration(opts.RotateEvery)
+		if err != nil {
+			return fmt.Errorf("invalid file rotate_every %q: %w", opts.RotateEvery, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("file rotate_every must be positive")
+		}
+	}
+	return nil
+}
+
+// validateExecTarget checks an exec:// url and its options.
+func validateExecTarget(u *url.URL, opts *ExecOptions) error {
+	if TargetPath(u) == "" {
+		return fmt.Errorf("exec url must name a command (exec:///path/to/command)")
+	}
+	if opts == nil || opts.Timeout == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(opts.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid exec timeout %q: %w", opts.Timeout, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("exec timeout must be positive")
+	}
+	return nil
+}
+
+// TargetPath returns the filesystem path of a file:// or exec:// url.
+// Both absolute (file:///var/log/x) and relative (file://logs/x) forms are
+// accepted; in the relative form the first segment parses as the host.
+func TargetPath(u *url.URL) string {
+	if u.Opaque != "" {
+		return u.Opaque
+	}
+	if u.Host == "" {
+		return u.Path
+	}
+	return u.Host + u.Path
+}
+
+// validateSASL checks the SASL block shared by broker-style targets.
+func validateSASL(sasl *SASLOptions) error {
+	if sasl == nil {
+		return nil
+	}
+	if !ValidSASLMechanisms[sasl.Mechanism] {
+		return fmt.Errorf("unknown sasl mechanism %q (valid: %s, %s, %s)", sasl.Mechanism, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+	}
+	if sasl.Username == "" {
+		return fmt.Errorf("sasl username is required")
+	}
+	if sasl.PasswordEnv == "" {
+		return fmt.Errorf("sasl password_env is required")
+	}
+	return nil
 }
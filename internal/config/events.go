@@ -0,0 +1,134 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EventFilter is a pipeline's events setting. In YAML it is either one
+// event (or "all") applied to every resource:
+//
+//	events: "created"
+//
+// or a list of events per resource, in which case the keys also name the
+// subscribed resources unless resources is set:
+//
+//	events:
+//	  messages: [created]
+//	  memberships: [created, deleted]
+type EventFilter struct {
+	// Event is the single-event form; "" means "all".
+	Event string
+
+	// PerResource is the per-resource form, in file order.
+	PerResource Selection
+}
+
+// IsZero reports whether the filter is unset, so omitempty drops it.
+func (f EventFilter) IsZero() bool {
+	return f.Event == "" && len(f.PerResource) == 0
+}
+
+// eventsFor returns the events selected for a resource. Resources without
+// a per-resource entry get the single event, or "all".
+func (f EventFilter) eventsFor(resource string) []string {
+	for _, sub := range f.PerResource {
+		if sub.Resource == resource {
+			return sub.Events
+		}
+	}
+	if f.Event != "" {
+		return []string{f.Event}
+	}
+	return []string{"all"}
+}
+
+// UnmarshalYAML accepts a scalar event or a mapping of resource names to
+// an event or a list of events.
+func (f *EventFilter) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*f = EventFilter{Event: node.Value}
+		return nil
+	case yaml.MappingNode:
+		var sel Selection
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			var events []string
+			switch value.Kind {
+			case yaml.ScalarNode:
+				events = []string{value.Value}
+			case yaml.SequenceNode:
+				if err := value.Decode(&events); err != nil {
+					return fmt.Errorf("line %d: events for %s must be a list of event names", value.Line, key.Value)
+				}
+			default:
+				return fmt.Errorf("line %d: events for %s must be an event or a list of events", value.Line, key.Value)
+			}
+			sel = append(sel, Subscription{Resource: key.Value, Events: events})
+		}
+		*f = EventFilter{PerResource: sel}
+		return nil
+	}
+	return fmt.Errorf("line %d: events must be an event name or a map of resource to events", node.Line)
+}
+
+// MarshalYAML writes the single-event form as a scalar and the
+// per-resource form as a mapping of flow-style lists.
+func (f EventFilter) MarshalYAML() (interface{}, error) {
+	if len(f.PerResource) == 0 {
+		return f.Event, nil
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, sub := range f.PerResource {
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, ev := range sub.Events {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: ev})
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: sub.Resource}, list)
+	}
+	return node, nil
+}
+
+// MarshalJSON mirrors MarshalYAML: a string, or an object of lists.
+func (f EventFilter) MarshalJSON() ([]byte, error) {
+	if len(f.PerResource) == 0 {
+		return json.Marshal(f.Event)
+	}
+	m := make(map[string][]string, len(f.PerResource))
+	for _, sub := range f.PerResource {
+		m[sub.Resource] = sub.Events
+	}
+	return json.Marshal(m)
+}
+
+// resourceHasEvent reports whether ev is one of the resource's events.
+// "all" is accepted for every resource.
+func resourceHasEvent(res *Resource, ev string) bool {
+	if ev == "all" {
+		return true
+	}
+	for _, e := range res.Events {
+		if e == ev {
+			return true
+		}
+	}
+	return false
+}
+
+// joinEvents lists a resource's events for error messages.
+func joinEvents(res *Resource) string {
+	events := "all"
+	for _, e := range res.Events {
+		if e != "all" {
+			events += ", " + e
+		}
+	}
+	return events
+}
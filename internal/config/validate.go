@@ -0,0 +1,408 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// Problem is one finding from config validation. Line is the 1-based line
+// in the YAML file it refers to, or 0 when the config did not come from a
+// file.
+type Problem struct {
+	Line    int
+	Message string
+	Warning bool
+}
+
+// String renders the problem with its line number, if known.
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// ValidationError carries every error found in a config, in file order,
+// along with any warnings found alongside them.
+type ValidationError struct {
+	Problems []Problem
+	Warnings []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Sprintf("config has %d problems:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// checker collects problems for one config. root is the parsed YAML
+// document, used only to look up line numbers; it may be nil.
+type checker struct {
+	root     *yaml.Node
+	problems []Problem
+}
+
+func (chk *checker) errorf(path []interface{}, format string, args ...interface{}) {
+	chk.problems = append(chk.problems, Problem{Line: lineOf(chk.root, path...), Message: fmt.Sprintf(format, args...)})
+}
+
+func (chk *checker) warnf(path []interface{}, format string, args ...interface{}) {
+	chk.problems = append(chk.problems, Problem{Line: lineOf(chk.root, path...), Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// at builds a node path for errorf and warnf.
+func at(path ...interface{}) []interface{} { return path }
+
+// lineOf follows path from the document root and returns the line of the
+// deepest node it reaches, so a missing key reports the line of its
+// parent. A string selects a mapping key; an int selects a sequence item
+// or the n-th value of a mapping.
+func lineOf(root *yaml.Node, path ...interface{}) int {
+	if root == nil {
+		return 0
+	}
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, step := range path {
+		next := childNode(node, step)
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node.Line
+}
+
+// childNode returns the child of node selected by step, or nil.
+func childNode(node *yaml.Node, step interface{}) *yaml.Node {
+	switch s := step.(type) {
+	case string:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == s {
+				return node.Content[i+1]
+			}
+		}
+	case int:
+		switch node.Kind {
+		case yaml.SequenceNode:
+			if s < len(node.Content) {
+				return node.Content[s]
+			}
+		case yaml.MappingNode:
+			if 2*s+1 < len(node.Content) {
+				return node.Content[2*s+1]
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the whole config, storing warnings in c.Warnings and
+// returning a *ValidationError holding every error, or nil.
+func (c *HookbusterConfig) validate(root *yaml.Node) error {
+	chk := &checker{root: root}
+	chk.checkConfig(c)
+	sort.SliceStable(chk.problems, func(i, j int) bool { return chk.problems[i].Line < chk.problems[j].Line })
+
+	var errs []Problem
+	c.Warnings = nil
+	for _, p := range chk.problems {
+		if p.Warning {
+			c.Warnings = append(c.Warnings, p)
+		} else {
+			errs = append(errs, p)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Problems: errs, Warnings: c.Warnings}
+	}
+	return nil
+}
+
+// checkConfig checks the top level: pipelines, the server block, and names
+// and tokens shared between pipelines.
+func (chk *checker) checkConfig(c *HookbusterConfig) {
+	if len(c.Pipelines) == 0 {
+		chk.errorf(at("pipelines"), "config must contain at least one pipeline")
+	}
+
+	chk.checkServer(c.Server)
+	chk.checkCache(c)
+
+	names := make(map[string]int)
+	tokens := make(map[string]int)
+	for i, p := range c.Pipelines {
+		prefix := fmt.Sprintf("pipeline %d (%q)", i, p.Name)
+		if p.Name != "" {
+			if first, ok := names[p.Name]; ok {
+				chk.errorf(at("pipelines", i, "name"), "%s: name is already used by pipeline %d (line %d)",
+					prefix, first, lineOf(chk.root, "pipelines", first, "name"))
+			} else {
+				names[p.Name] = i
+			}
+		}
+		if p.TokenEnv != "" {
+			if first, ok := tokens[p.TokenEnv]; ok {
+				chk.warnf(at("pipelines", i, "token_env"), "%s: token_env %s is also used by pipeline %d (%q); each pipeline registers its own device and gets its own copy of every event",
+					prefix, p.TokenEnv, first, c.Pipelines[first].Name)
+			} else {
+				tokens[p.TokenEnv] = i
+			}
+		}
+		chk.checkPipeline(i, prefix, p, c.Server == nil)
+	}
+}
+
+// checkServer checks the optional stream server block.
+func (chk *checker) checkServer(s *ServerOptions) {
+	if s == nil {
+		return
+	}
+	if s.Listen != "" {
+		if _, _, err := net.SplitHostPort(s.Listen); err != nil {
+			chk.errorf(at("server", "listen"), "server: invalid listen address %q: %v", s.Listen, err)
+		}
+	}
+	for i, env := range s.TokenEnvs {
+		if env == "" {
+			chk.errorf(at("server", "token_envs", i), "server: token_envs must not contain empty names")
+		}
+	}
+	listen := s.Listen
+	if listen == "" {
+		listen = DefaultServerListen
+	}
+	if len(s.TokenEnvs) == 0 && !IsLoopbackListen(listen) {
+		chk.errorf(at("server", "listen"), "server: token_envs is required when listening on %s, which is not a loopback address", listen)
+	}
+	for i, origin := range s.AllowedOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			chk.errorf(at("server", "allowed_origins", i), "server: invalid allowed origin %q, want scheme://host[:port]", origin)
+		}
+	}
+	if s.Buffer < 0 {
+		chk.errorf(at("server", "buffer"), "server: buffer must not be negative")
+	}
+}
+
+// checkCache checks the optional metadata cache block.
+func (chk *checker) checkCache(c *HookbusterConfig) {
+	if c.Cache == nil {
+		return
+	}
+	if c.Cache.TTL != "" {
+		if d, err := time.ParseDuration(c.Cache.TTL); err != nil {
+			chk.errorf(at("cache", "ttl"), "cache: invalid ttl %q: %v", c.Cache.TTL, err)
+		} else if d <= 0 {
+			chk.errorf(at("cache", "ttl"), "cache: ttl must be positive")
+		}
+	}
+	if c.Cache.MaxEntries < 0 {
+		chk.errorf(at("cache", "max_entries"), "cache: max_entries must not be negative")
+	}
+	for _, p := range c.Pipelines {
+		if p.Enrich {
+			return
+		}
+	}
+	chk.warnf(at("cache"), "cache: no pipeline sets enrich, so the cache is unused")
+}
+
+// checkPipeline checks a single pipeline for required fields and valid
+// values. Targets are optional when the stream server is enabled.
+func (chk *checker) checkPipeline(i int, prefix string, p Pipeline, requireTargets bool) {
+	if p.TokenEnv == "" {
+		chk.errorf(at("pipelines", i), "%s: token_env is required", prefix)
+	}
+	if p.Mode != "" && !ValidModes[p.Mode] {
+		chk.errorf(at("pipelines", i, "mode"), "%s: unknown mode %q (valid: %s, %s)", prefix, p.Mode, ModeFanout, ModeRoundRobin)
+	}
+	if p.IDs != "" && !ValidIDForms[p.IDs] {
+		chk.errorf(at("pipelines", i, "ids"), "%s: unknown ids %q (valid: %s, %s, %s)", prefix, p.IDs, IDsUUID, IDsHydra, IDsBoth)
+	}
+
+	if requireTargets && len(p.Targets) == 0 {
+		chk.errorf(at("pipelines", i, "targets"), "%s: at least one target is required", prefix)
+	}
+	urls := make(map[string]int)
+	for j, t := range p.Targets {
+		path := at("pipelines", i, "targets", j, "url")
+		if t.URL == "" {
+			chk.errorf(path, "%s: target url must not be empty", prefix)
+			continue
+		}
+		if first, ok := urls[t.URL]; ok {
+			chk.errorf(path, "%s: target %q is listed more than once (first at line %d)",
+				prefix, t.URL, lineOf(chk.root, "pipelines", i, "targets", first, "url"))
+			continue
+		}
+		urls[t.URL] = j
+		if err := ValidateTarget(t); err != nil {
+			chk.errorf(path, "%s: target %q: %v", prefix, t.URL, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for j, r := range p.Resources {
+		path := at("pipelines", i, "resources", j)
+		if _, ok := Resources[r]; !ok {
+			chk.errorf(path, "%s: unknown resource %q", prefix, r)
+			continue
+		}
+		if seen[r] {
+			chk.warnf(path, "%s: resource %s is listed more than once", prefix, r)
+		}
+		seen[r] = true
+	}
+
+	chk.checkEvents(i, prefix, p)
+	chk.checkAttachments(i, prefix, p.Attachments)
+	chk.checkIgnoreActors(i, prefix, p.IgnoreActors)
+}
+
+// checkIgnoreActors checks that each ignored actor is an email address or
+// a person ID.
+func (chk *checker) checkIgnoreActors(i int, prefix string, actors []string) {
+	seen := make(map[string]int)
+	for j, a := range actors {
+		path := at("pipelines", i, "ignore_actors", j)
+		if a == "" {
+			chk.errorf(path, "%s: ignore_actors must not contain empty entries", prefix)
+			continue
+		}
+		if typ, _, ok := hydra.Parse(a); ok && typ != hydra.People {
+			chk.errorf(path, "%s: ignore_actors: %q is a %s id, not a person id", prefix, a, typ)
+			continue
+		}
+		key := strings.ToLower(a)
+		if first, ok := seen[key]; ok {
+			chk.warnf(path, "%s: ignore_actors: %q is listed more than once (first at line %d)",
+				prefix, a, lineOf(chk.root, "pipelines", i, "ignore_actors", first))
+			continue
+		}
+		seen[key] = j
+	}
+}
+
+// checkAttachments checks the pipeline's attachments block: the mode and
+// the settings that mode needs.
+func (chk *checker) checkAttachments(i int, prefix string, a *AttachmentOptions) {
+	if a == nil {
+		return
+	}
+	base := at("pipelines", i, "attachments")
+	if a.Mode == "" {
+		chk.errorf(base, "%s: attachments: mode is required (valid: %s, %s, %s)", prefix, AttachmentsInline, AttachmentsDir, AttachmentsS3)
+	} else if !ValidAttachmentModes[a.Mode] {
+		chk.errorf(at("pipelines", i, "attachments", "mode"), "%s: attachments: unknown mode %q (valid: %s, %s, %s)",
+			prefix, a.Mode, AttachmentsInline, AttachmentsDir, AttachmentsS3)
+	}
+	if a.MaxSizeMB < 0 {
+		chk.errorf(at("pipelines", i, "attachments", "max_size_mb"), "%s: attachments: max_size_mb must not be negative", prefix)
+	}
+
+	if a.Mode == AttachmentsDir && a.Dir == "" {
+		chk.errorf(base, "%s: attachments: dir is required in %s mode", prefix, AttachmentsDir)
+	}
+	if a.Mode != AttachmentsDir && a.Dir != "" {
+		chk.warnf(at("pipelines", i, "attachments", "dir"), "%s: attachments: dir is ignored in %s mode", prefix, a.Mode)
+	}
+	if a.Mode != AttachmentsS3 {
+		if a.S3 != nil {
+			chk.warnf(at("pipelines", i, "attachments", "s3"), "%s: attachments: s3 is ignored in %s mode", prefix, a.Mode)
+		}
+		return
+	}
+
+	if a.S3 == nil {
+		chk.errorf(base, "%s: attachments: s3 is required in %s mode", prefix, AttachmentsS3)
+		return
+	}
+	s3 := at("pipelines", i, "attachments", "s3")
+	if u, err := url.Parse(a.S3.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		chk.errorf(at("pipelines", i, "attachments", "s3", "endpoint"), "%s: attachments: s3.endpoint must be an http or https url with a host", prefix)
+	}
+	if a.S3.Bucket == "" {
+		chk.errorf(s3, "%s: attachments: s3.bucket is required", prefix)
+	}
+	if a.S3.AccessKeyEnv == "" || a.S3.SecretKeyEnv == "" {
+		chk.errorf(s3, "%s: attachments: s3.access_key_env and s3.secret_key_env are required", prefix)
+	}
+	if a.S3.PublicURL != "" {
+		if u, err := url.Parse(a.S3.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			chk.errorf(at("pipelines", i, "attachments", "s3", "public_url"), "%s: attachments: s3.public_url must be an absolute url", prefix)
+		}
+	}
+}
+
+// checkEvents checks the pipeline's events against the events of each
+// subscribed resource. A single event must exist on every subscribed
+// resource, since the listener refuses to start otherwise.
+func (chk *checker) checkEvents(i int, prefix string, p Pipeline) {
+	if len(p.Events.PerResource) == 0 {
+		if p.Events.Event == "" {
+			return
+		}
+		for _, sub := range p.Selection() {
+			res, ok := Resources[sub.Resource]
+			if ok && !resourceHasEvent(res, p.Events.Event) {
+				chk.errorf(at("pipelines", i, "events"), "%s: events: resource %s has no event %q (valid: %s)",
+					prefix, sub.Resource, p.Events.Event, joinEvents(res))
+			}
+		}
+		return
+	}
+
+	listed := make(map[string]bool)
+	for _, r := range p.Resources {
+		listed[r] = true
+	}
+	seen := make(map[string]bool)
+	for k, sub := range p.Events.PerResource {
+		path := at("pipelines", i, "events", k)
+		res, ok := Resources[sub.Resource]
+		if !ok {
+			chk.errorf(path, "%s: events: unknown resource %q", prefix, sub.Resource)
+			continue
+		}
+		if seen[sub.Resource] {
+			chk.errorf(path, "%s: events: %s is listed more than once", prefix, sub.Resource)
+			continue
+		}
+		seen[sub.Resource] = true
+		if len(p.Resources) > 0 && !listed[sub.Resource] {
+			chk.errorf(path, "%s: events: %s is not in resources", prefix, sub.Resource)
+		}
+		if len(sub.Events) == 0 {
+			chk.errorf(path, "%s: events: %s has an empty event list", prefix, sub.Resource)
+		}
+		for j, ev := range sub.Events {
+			if !resourceHasEvent(res, ev) {
+				chk.errorf(at("pipelines", i, "events", k, j), "%s: events: resource %s has no event %q (valid: %s)",
+					prefix, sub.Resource, ev, joinEvents(res))
+			}
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ── Validation tests ────────────────────────────────────────────────────
+
+func TestLoadConfig_ReportsAllProblemsWithLines(t *testing.T) {
+	yaml := `pipelines:
+  - name: "bot"
+    mode: "sideways"
+    resources: ["messages", "widgets"]
+    targets:
+      - url: "gopher://localhost"
+  - name: "bot"
+    token_env: "WEBEX_TOKEN"
+    targets:
+      - url: "http://localhost:8080"
+`
+	_, err := LoadConfig(writeTestConfig(t, yaml))
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("LoadConfig() error = %v, want *ValidationError", err)
+	}
+
+	want := []struct {
+		line int
+		msg  string
+	}{
+		{2, "token_env is required"},
+		{3, `unknown mode "sideways"`},
+		{4, `unknown resource "widgets"`},
+		{6, `unsupported scheme "gopher"`},
+		{7, "name is already used by pipeline 0 (line 2)"},
+	}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(verr.Problems), len(want), err)
+	}
+	for i, w := range want {
+		p := verr.Problems[i]
+		if p.Line != w.line || !strings.Contains(p.Message, w.msg) {
+			t.Errorf("problem %d = %q, want line %d containing %q", i, p, w.line, w.msg)
+		}
+	}
+	if !strings.Contains(err.Error(), "config has 5 problems") {
+		t.Errorf("error = %q, want a problem count", err.Error())
+	}
+}
+
+func TestLoadConfig_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+		line   int
+	}{
+		{
+			name:   "scalar event missing from a resource",
+			config: "resources: [\"messages\", \"rooms\"]\n    events: \"deleted\"",
+			want:   `resource rooms has no event "deleted"`,
+			line:   5,
+		},
+		{
+			name:   "scalar event on firehose",
+			config: "events: \"deleted\"",
+			want:   `resource rooms has no event "deleted"`,
+			line:   4,
+		},
+		{
+			name:   "per-resource event line",
+			config: "events:\n      messages: [created, nope]",
+			want:   `resource messages has no event "nope"`,
+			line:   5,
+		},
+		{
+			name:   "duplicate target",
+			config: "targets:\n      - url: \"http://localhost:8080\"\n      - url: \"http://localhost:8080\"",
+			want:   "is listed more than once (first at line 5)",
+			line:   6,
+		},
+		{
+			name:   "missing scheme",
+			config: "targets:\n      - url: \"/hooks\"",
+			want:   "url has no scheme",
+			line:   5,
+		},
+		{
+			name:   "http without host",
+			config: "targets:\n      - url: \"http:///hooks\"",
+			want:   "http url must include a host",
+			line:   5,
+		},
+		{
+			name:   "unknown attachments mode",
+			config: "attachments:\n      mode: \"ftp\"",
+			want:   `attachments: unknown mode "ftp"`,
+			line:   5,
+		},
+		{
+			name:   "dir mode without dir",
+			config: "attachments:\n      mode: dir",
+			want:   "attachments: dir is required in dir mode",
+			line:   5,
+		},
+		{
+			name:   "s3 without bucket",
+			config: "attachments:\n      mode: s3\n      s3:\n        endpoint: \"http://minio:9000\"\n        access_key_env: S3_KEY\n        secret_key_env: S3_SECRET",
+			want:   "attachments: s3.bucket is required",
+			line:   7,
+		},
+		{
+			name:   "s3 endpoint without scheme",
+			config: "attachments:\n      mode: s3\n      s3:\n        endpoint: \"minio:9000\"\n        bucket: files\n        access_key_env: S3_KEY\n        secret_key_env: S3_SECRET",
+			want:   "attachments: s3.endpoint must be an http or https url",
+			line:   7,
+		},
+		{
+			name:   "unknown ids form",
+			config: "ids: \"base64\"",
+			want:   `unknown ids "base64" (valid: uuid, hydra, both)`,
+			line:   4,
+		},
+		{
+			name:   "empty ignored actor",
+			config: "ignore_actors: [\"bot@webex.bot\", \"\"]",
+			want:   "ignore_actors must not contain empty entries",
+			line:   4,
+		},
+		{
+			name:   "ignored actor is not a person",
+			config: "ignore_actors:\n      - \"Y2lzY29zcGFyazovL3VzL1JPT00vYWJj\"",
+			want:   "is a ROOM id, not a person id",
+			line:   5,
+		},
+	}
+	for _, tt := range tests {
+		yaml := `pipelines:
+  - name: "bot"
+    token_env: "WEBEX_TOKEN"
+    ` + tt.config + "\n"
+		if !strings.Contains(tt.config, "targets:") {
+			yaml += "    targets:\n      - url: \"stdout://\"\n"
+		}
+		_, err := LoadConfig(writeTestConfig(t, yaml))
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: error = %v, want *ValidationError", tt.name, err)
+			continue
+		}
+		p := verr.Problems[0]
+		if !strings.Contains(p.Message, tt.want) || p.Line != tt.line {
+			t.Errorf("%s: problem = %q, want line %d containing %q", tt.name, p, tt.line, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_Warnings(t *testing.T) {
+	yaml := `pipelines:
+  - name: "a"
+    token_env: "WEBEX_TOKEN"
+    resources: ["messages", "messages"]
+    targets:
+      - url: "stdout://"
+  - name: "b"
+    token_env: "WEBEX_TOKEN"
+    targets:
+      - url: "stdout://"
+`
+	cfg, err := LoadConfig(writeTestConfig(t, yaml))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Fatalf("warnings = %v, want 2", cfg.Warnings)
+	}
+	if w := cfg.Warnings[0]; w.Line != 4 || !strings.Contains(w.Message, "resource messages is listed more than once") {
+		t.Errorf("warning 0 = %q", w)
+	}
+	if w := cfg.Warnings[1]; w.Line != 8 || !strings.Contains(w.Message, `token_env WEBEX_TOKEN is also used by pipeline 0 ("a")`) {
+		t.Errorf("warning 1 = %q", w)
+	}
+}
+
+func TestLoadConfig_OptionWarnings(t *testing.T) {
+	yaml := `pipelines:
+  - name: "a"
+    token_env: "WEBEX_TOKEN"
+    attachments:
+      mode: inline
+      dir: /var/lib/hookbuster
+    ignore_actors: ["bot@webex.bot", "Bot@webex.bot"]
+    targets:
+      - url: "stdout://"
+`
+	cfg, err := LoadConfig(writeTestConfig(t, yaml))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Fatalf("warnings = %v, want 2", cfg.Warnings)
+	}
+	if w := cfg.Warnings[0]; w.Line != 6 || !strings.Contains(w.Message, "dir is ignored in inline mode") {
+		t.Errorf("warning 0 = %q, want dir ignored on line 6", w)
+	}
+	if w := cfg.Warnings[1]; w.Line != 7 || !strings.Contains(w.Message, `"Bot@webex.bot" is listed more than once`) {
+		t.Errorf("warning 1 = %q, want duplicate ignored actor on line 7", w)
+	}
+}
+
+func TestLoadConfig_Cache(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   string
+		enrich  bool
+		wantErr string
+		warning string
+	}{
+		{name: "valid", cache: "ttl: \"5m\"\n  max_entries: 500", enrich: true},
+		{name: "bad ttl", cache: "ttl: \"soon\"", enrich: true, wantErr: `line 2: cache: invalid ttl "soon"`},
+		{name: "negative ttl", cache: "ttl: \"-1m\"", enrich: true, wantErr: "line 2: cache: ttl must be positive"},
+		{name: "negative max_entries", cache: "max_entries: -1", enrich: true, wantErr: "line 2: cache: max_entries must not be negative"},
+		{name: "unused", cache: "ttl: \"5m\"", warning: "cache: no pipeline sets enrich"},
+	}
+	for _, tt := range tests {
+		yaml := "cache:\n  " + tt.cache + "\npipelines:\n  - token_env: \"WEBEX_TOKEN\"\n    targets:\n      - url: \"stdout://\"\n"
+		if tt.enrich {
+			yaml += "    enrich: true\n"
+		}
+		cfg, err := LoadConfig(writeTestConfig(t, yaml))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: error: %v", tt.name, err)
+			continue
+		}
+		if tt.warning == "" && len(cfg.Warnings) > 0 {
+			t.Errorf("%s: warnings = %v", tt.name, cfg.Warnings)
+		}
+		if tt.warning != "" && (len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0].Message, tt.warning)) {
+			t.Errorf("%s: warnings = %v, want %q", tt.name, cfg.Warnings, tt.warning)
+		}
+	}
+}
+
+func TestValidate_NoLines(t *testing.T) {
+	cfg := &HookbusterConfig{Pipelines: []Pipeline{{Name: "bot", Targets: []Target{{URL: "stdout://"}}}}}
+	err := cfg.Validate()
+	if err == nil || err.Error() != `pipeline 0 ("bot"): token_env is required` {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateTarget_Schemes(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"http://localhost:8080/hook", ""},
+		{"https://example.com", ""},
+		{"stdout://", ""},
+		{"localhost:8080", `unsupported scheme "localhost"`},
+		{"ftp://example.com", `unsupported scheme "ftp"`},
+		{"example.com/hook", "url has no scheme"},
+		{"https://", "https url must include a host"},
+	}
+	for _, tt := range tests {
+		err := ValidateTarget(Target{URL: tt.url})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateTarget(%q) error: %v", tt.url, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateTarget(%q) error = %v, want %q", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -4,7 +4,12 @@
 
 package display
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
 
 // ANSI color codes
 const (
@@ -12,6 +17,7 @@ const (
 	bold    = "\033[1m"
 	red     = "\033[31m"
 	green   = "\033[32m"
+	yellow  = "\033[33m"
 	blue    = "\033[34m"
 	magenta = "\033[35m"
 
@@ -19,6 +25,27 @@ const (
 	colorFmt = "%s%s%s"
 )
 
+// output receives hookbuster's status and error lines; see Println.
+var (
+	outputMu sync.Mutex
+	output   io.Writer = os.Stdout
+)
+
+// SetOutput sends the lines written by Println to w. A stdout:// target
+// moves them to stderr so stdout carries nothing but events.
+func SetOutput(w io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	output = w
+}
+
+// Println writes a status or error line, normally to stdout.
+func Println(text string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprintln(output, text)
+}
+
 // Question formats text as a bold prompt.
 func Question(text string) string {
 	return fmt.Sprintf(colorFmt+" ", bold, text, reset)
@@ -39,6 +66,11 @@ func Error(text string) string {
 	return fmt.Sprintf(colorFmt, red, text, reset)
 }
 
+// Warning formats text as a yellow warning message.
+func Warning(text string) string {
+	return fmt.Sprintf(colorFmt, yellow, text, reset)
+}
+
 // Highlight formats text in magenta for emphasis.
 func Highlight(text string) string {
 	return fmt.Sprintf(colorFmt, magenta, text, reset)
@@ -55,5 +87,5 @@ func Welcome() {
                                                         
   GO Webex WebSocket-to-HTTP Event Bridge 
 `
-	fmt.Println(Info(banner))
+	Println(Info(banner))
 }
@@ -1,6 +1,7 @@
 package display
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -48,6 +49,16 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestWarning(t *testing.T) {
+	result := Warning("careful")
+	if !strings.Contains(result, "careful") {
+		t.Errorf("Warning() should contain the text, got %q", result)
+	}
+	if !strings.Contains(result, yellow) {
+		t.Error("Warning() should contain yellow ANSI code")
+	}
+}
+
 func TestHighlight(t *testing.T) {
 	result := Highlight("important")
 	if !strings.Contains(result, "important") {
@@ -66,6 +77,7 @@ func TestAllFormattedStringsEndWithReset(t *testing.T) {
 		{"Answer", Answer},
 		{"Info", Info},
 		{"Error", Error},
+		{"Warning", Warning},
 		{"Highlight", Highlight},
 	}
 
@@ -76,3 +88,14 @@ func TestAllFormattedStringsEndWithReset(t *testing.T) {
 		}
 	}
 }
+
+func TestSetOutput_RedirectsPrintln(t *testing.T) {
+	var buf strings.Builder
+	SetOutput(&buf)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	Println(Info("status"))
+	if buf.String() != Info("status")+"\n" {
+		t.Errorf("output = %q, want the line written to the new writer", buf.String())
+	}
+}
@@ -0,0 +1,204 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// amqpSender publishes events to a RabbitMQ exchange on a channel in
+// confirm mode: a publish only succeeds once the broker acks it. The
+// connection is dialled lazily and dropped when it closes or a publish
+// fails, so the next Send or Probe reconnects after a broker restart.
+type amqpSender struct {
+	mu         sync.Mutex
+	url        string
+	host       string
+	pipeline   string
+	exchange   string
+	routingKey string
+	mandatory  bool
+	cfg        amqp.Config
+	conn       *amqp.Connection
+	ch         *amqp.Channel
+	returns    chan amqp.Return
+}
+
+func newAMQPSender(pipeline string, target config.Target, u *url.URL) (Sender, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("amqp target %q must be amqp://host[:port]/vhost", target.URL)
+	}
+
+	s := &amqpSender{
+		url:        target.URL,
+		host:       u.Host,
+		pipeline:   pipeline,
+		exchange:   config.DefaultAMQPExchange,
+		routingKey: config.DefaultAMQPRoutingKey,
+		mandatory:  true,
+		cfg: amqp.Config{
+			Heartbeat:  10 * time.Second,
+			Dial:       amqp.DefaultDial(healthCheckTimeout),
+			Properties: amqp.Table{"connection_name": "hookbuster"},
+		},
+	}
+
+	if a := target.AMQP; a != nil {
+		if a.Exchange != "" {
+			s.exchange = a.Exchange
+		}
+		if a.RoutingKey != "" {
+			s.routingKey = a.RoutingKey
+		}
+		if a.Mandatory != nil {
+			s.mandatory = *a.Mandatory
+		}
+		if a.Username != "" {
+			s.cfg.SASL = []amqp.Authentication{&amqp.PlainAuth{
+				Username: a.Username,
+				Password: os.Getenv(a.PasswordEnv),
+			}}
+		}
+
+		tlsCfg, err := buildTLSConfig(a.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.cfg.TLSClientConfig = tlsCfg
+	}
+
+	return s, nil
+}
+
+// Send publishes the event as compact JSON and waits for the broker's
+// confirm. A nack, or a mandatory message returned as unroutable, counts as
+// a failed delivery.
+func (s *amqpSender) Send(event config.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.connectLocked(); err != nil {
+		return err
+	}
+
+	key := expandRoutingTemplate(s.routingKey, s.pipeline, event)
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Type:         event.Resource + ":" + event.Event,
+		MessageId:    eventDataString(event, "id"),
+		Body:         payload,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	confirm, err := s.ch.PublishWithDeferredConfirmWithContext(ctx, s.exchange, key, s.mandatory, false, msg)
+	if err != nil {
+		s.resetLocked()
+		return fmt.Errorf("amqp publish to %s/%s failed: %w", s.exchange, key, err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		s.resetLocked()
+		return fmt.Errorf("amqp confirm for %s/%s failed: %w", s.exchange, key, err)
+	}
+	if !acked {
+		return fmt.Errorf("amqp broker nacked publish to %s/%s", s.exchange, key)
+	}
+
+	// The broker sends basic.return before the ack, so any return for this
+	// publish is already buffered by the time the confirm arrives.
+	select {
+	case ret := <-s.returns:
+		return fmt.Errorf("amqp publish to %s/%s unroutable: %s", s.exchange, key, ret.ReplyText)
+	default:
+	}
+
+	display.Println(display.Info(fmt.Sprintf("event forwarded to amqp exchange %s (routing key %s)", s.exchange, key)))
+	return nil
+}
+
+// Probe reconnects if the connection was dropped or closed by the broker.
+func (s *amqpSender) Probe() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connectLocked()
+}
+
+// Close closes the channel and connection.
+func (s *amqpSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.ch = nil
+	s.returns = nil
+	if err == amqp.ErrClosed {
+		return nil
+	}
+	return err
+}
+
+// connectLocked dials the broker and opens a confirm-mode channel unless a
+// live one already exists. Callers must hold s.mu.
+func (s *amqpSender) connectLocked() error {
+	if s.conn != nil && !s.conn.IsClosed() && s.ch != nil && !s.ch.IsClosed() {
+		return nil
+	}
+	s.resetLocked()
+
+	conn, err := amqp.DialConfig(s.url, s.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to amqp broker %s: %w", s.host, err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to open amqp channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to enable amqp publisher confirms: %w", err)
+	}
+
+	s.conn = conn
+	s.ch = ch
+	s.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	return nil
+}
+
+// resetLocked drops the current connection so the next call redials.
+// Callers must hold s.mu.
+func (s *amqpSender) resetLocked() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.conn = nil
+	s.ch = nil
+	s.returns = nil
+}
@@ -0,0 +1,75 @@
+package forwarder
+
+import (
+	"testing"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+func TestAMQPSender_Defaults(t *testing.T) {
+	s, err := NewSender("bot", config.Target{URL: "amqp://localhost:5672/webex"})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	a := s.(*amqpSender)
+
+	if a.exchange != config.DefaultAMQPExchange {
+		t.Errorf("exchange = %q, want %q", a.exchange, config.DefaultAMQPExchange)
+	}
+	if !a.mandatory {
+		t.Error("mandatory should default to true")
+	}
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created"}
+	if got := expandRoutingTemplate(a.routingKey, a.pipeline, event); got != "messages.created" {
+		t.Errorf("routing key = %q, want %q", got, "messages.created")
+	}
+}
+
+func TestAMQPSender_Options(t *testing.T) {
+	mandatory := false
+	t.Setenv("AMQP_PASSWORD", "secret")
+	s, err := NewSender("bot", config.Target{
+		URL: "amqps://rabbit.example.com/",
+		AMQP: &config.AMQPOptions{
+			Exchange:    "webex.events",
+			RoutingKey:  "{pipeline}.{resource}.{event}",
+			Mandatory:   &mandatory,
+			Username:    "hookbuster",
+			PasswordEnv: "AMQP_PASSWORD",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	a := s.(*amqpSender)
+
+	if a.exchange != "webex.events" || a.mandatory {
+		t.Errorf("sender = %+v, want exchange and mandatory overridden", a)
+	}
+	if len(a.cfg.SASL) != 1 || a.cfg.SASL[0].Response() != "\x00hookbuster\x00secret" {
+		t.Error("username and password_env should configure PLAIN auth")
+	}
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated"}
+	if got := expandRoutingTemplate(a.routingKey, a.pipeline, event); got != "bot.rooms.updated" {
+		t.Errorf("routing key = %q, want %q", got, "bot.rooms.updated")
+	}
+}
+
+func TestAMQPSender_UnreachableBroker(t *testing.T) {
+	s, err := NewSender("bot", config.Target{URL: "amqp://127.0.0.1:1/"})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail for unreachable broker")
+	}
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	if err := s.Send(event); err == nil {
+		t.Error("Send() should fail for unreachable broker")
+	}
+}
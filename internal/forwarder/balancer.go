@@ -6,7 +6,6 @@ package forwarder
 
 import (
 	"fmt"
-	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,7 +23,7 @@ const (
 	// unhealthy targets.
 	healthCheckInterval = 10 * time.Second
 
-	// healthCheckTimeout is the HTTP timeout for a single health probe.
+	// healthCheckTimeout is the timeout for a single health probe.
 	healthCheckTimeout = 5 * time.Second
 )
 
@@ -32,6 +31,7 @@ const (
 type targetState struct {
 	mu        sync.Mutex
 	url       string
+	sender    Sender
 	healthy   bool
 	failCount int
 }
@@ -78,11 +78,17 @@ type Balancer struct {
 
 // NewBalancer creates a round-robin balancer for the given targets and starts
 // the background health-check goroutine.
-func NewBalancer(name string, targets []config.Target) *Balancer {
+func NewBalancer(name string, targets []config.Target) (*Balancer, error) {
+	senders, err := NewSenders(name, targets)
+	if err != nil {
+		return nil, err
+	}
+
 	states := make([]*targetState, len(targets))
 	for i, t := range targets {
 		states[i] = &targetState{
 			url:     t.URL,
+			sender:  senders[i],
 			healthy: true,
 		}
 	}
@@ -96,7 +102,7 @@ func NewBalancer(name string, targets []config.Target) *Balancer {
 	b.wg.Add(1)
 	go b.healthCheckLoop()
 
-	return b
+	return b, nil
 }
 
 // Forward sends the event to the next healthy target using round-robin.
@@ -119,7 +125,7 @@ func (b *Balancer) Forward(event config.WebhookEvent) error {
 			continue
 		}
 
-		err := ForwardToURL(ts.url, event)
+		err := ts.sender.Send(event)
 		if err == nil {
 			ts.markHealthy()
 			return nil
@@ -128,7 +134,7 @@ func (b *Balancer) Forward(event config.WebhookEvent) error {
 		lastErr = err
 		becameUnhealthy := ts.markFailed()
 		if becameUnhealthy {
-			fmt.Println(display.Error(fmt.Sprintf("[%s] target %s marked unhealthy after %d consecutive failures",
+			display.Println(display.Error(fmt.Sprintf("[%s] target %s marked unhealthy after %d consecutive failures",
 				b.name, ts.url, maxConsecutiveFailures)))
 		}
 	}
@@ -139,10 +145,16 @@ func (b *Balancer) Forward(event config.WebhookEvent) error {
 	return fmt.Errorf("no healthy targets available")
 }
 
-// Stop shuts down the background health-check goroutine.
+// Stop shuts down the background health-check goroutine and closes the
+// target senders.
 func (b *Balancer) Stop() {
 	close(b.stopCh)
 	b.wg.Wait()
+	for _, ts := range b.targets {
+		if err := ts.sender.Close(); err != nil {
+			display.Println(display.Error(fmt.Sprintf("[%s] error closing target %s: %s", b.name, ts.url, err.Error())))
+		}
+	}
 }
 
 // healthCheckLoop periodically probes unhealthy targets and marks them
@@ -150,7 +162,6 @@ func (b *Balancer) Stop() {
 func (b *Balancer) healthCheckLoop() {
 	defer b.wg.Done()
 
-	client := &http.Client{Timeout: healthCheckTimeout}
 	ticker := time.NewTicker(healthCheckInterval)
 	defer ticker.Stop()
 
@@ -159,28 +170,26 @@ func (b *Balancer) healthCheckLoop() {
 		case <-b.stopCh:
 			return
 		case <-ticker.C:
-			b.probeUnhealthy(client)
+			b.probeUnhealthy()
 		}
 	}
 }
 
-// probeUnhealthy sends a HEAD request to each unhealthy target. If the
-// target responds (any status code), it is marked healthy again.
-func (b *Balancer) probeUnhealthy(client *http.Client) {
+// probeUnhealthy probes each unhealthy target through its sender (a HEAD
+// request for HTTP targets). If the probe succeeds, it is marked healthy again.
+func (b *Balancer) probeUnhealthy() {
 	for _, ts := range b.targets {
 		if ts.isHealthy() {
 			continue
 		}
 
-		resp, err := client.Head(ts.url)
-		if err != nil {
+		if err := ts.sender.Probe(); err != nil {
 			continue
 		}
-		resp.Body.Close()
 
-		// Any response means the target is reachable again
+		// A successful probe means the target is reachable again
 		if ts.markHealthy() {
-			fmt.Println(display.Info(fmt.Sprintf("[%s] target %s recovered, marked healthy",
+			display.Println(display.Info(fmt.Sprintf("[%s] target %s recovered, marked healthy",
 				b.name, ts.url)))
 		}
 	}
@@ -6,7 +6,6 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
-	"time"
 
 	"github.com/tejzpr/webex-go-hookbuster/internal/config"
 )
@@ -23,7 +22,10 @@ func targetsFromURLs(urls ...string) []config.Target {
 // helper: new balancer that we always stop in cleanup
 func newTestBalancer(t *testing.T, name string, targets []config.Target) *Balancer {
 	t.Helper()
-	b := NewBalancer(name, targets)
+	b, err := NewBalancer(name, targets)
+	if err != nil {
+		t.Fatalf("NewBalancer() error: %v", err)
+	}
 	t.Cleanup(b.Stop)
 	return b
 }
@@ -257,8 +259,7 @@ func TestBalancer_ProbeRecovery(t *testing.T) {
 	b.targets[0].mu.Unlock()
 
 	// Manually run the probe
-	client := &http.Client{Timeout: healthCheckTimeout}
-	b.probeUnhealthy(client)
+	b.probeUnhealthy()
 
 	if !b.targets[0].isHealthy() {
 		t.Error("target should be healthy after successful probe")
@@ -274,8 +275,7 @@ func TestBalancer_ProbeDoesNotRecoverUnreachable(t *testing.T) {
 	b.targets[0].healthy = false
 	b.targets[0].mu.Unlock()
 
-	client := &http.Client{Timeout: 1 * time.Second}
-	b.probeUnhealthy(client)
+	b.probeUnhealthy()
 
 	if b.targets[0].isHealthy() {
 		t.Error("unreachable target should remain unhealthy after probe")
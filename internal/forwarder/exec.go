@@ -0,0 +1,117 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// maxExecStderr caps how much of a failing command's stderr is included
+// in the delivery error.
+const maxExecStderr = 512
+
+// execSender runs a command once per event with the event JSON on stdin.
+// A non-zero exit status or a timeout counts as a failed delivery.
+type execSender struct {
+	command  string
+	args     []string
+	timeout  time.Duration
+	pipeline string
+}
+
+func newExecSender(pipeline string, target config.Target, u *url.URL) (Sender, error) {
+	command := config.TargetPath(u)
+	if command == "" {
+		return nil, fmt.Errorf("exec target %q must name a command", target.URL)
+	}
+
+	timeoutStr := config.DefaultExecTimeout
+	var args []string
+	if e := target.Exec; e != nil {
+		args = e.Args
+		if e.Timeout != "" {
+			timeoutStr = e.Timeout
+		}
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec timeout %q: %w", timeoutStr, err)
+	}
+
+	return &execSender{
+		command:  command,
+		args:     args,
+		timeout:  timeout,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Send runs the command with the event as a single JSON line on stdin. The
+// pipeline, resource and event are also exported as HOOKBUSTER_* env vars
+// so scripts can branch without parsing JSON.
+func (s *execSender) Send(event config.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.command, s.args...)
+	// Don't wait on grandchildren that inherited stderr after a timeout kill
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"HOOKBUSTER_PIPELINE="+routingPipeline(s.pipeline),
+		"HOOKBUSTER_RESOURCE="+event.Resource,
+		"HOOKBUSTER_EVENT="+event.Event,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("exec %s timed out after %s", s.command, s.timeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxExecStderr {
+			msg = msg[len(msg)-maxExecStderr:]
+		}
+		if msg != "" {
+			return fmt.Errorf("exec %s failed: %w: %s", s.command, err, msg)
+		}
+		return fmt.Errorf("exec %s failed: %w", s.command, err)
+	}
+
+	display.Println(display.Info(fmt.Sprintf("event forwarded to exec %s", s.command)))
+	return nil
+}
+
+// Probe checks the command can still be found and executed.
+func (s *execSender) Probe() error {
+	if _, err := exec.LookPath(s.command); err != nil {
+		return fmt.Errorf("exec target: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op; each Send runs its own process.
+func (s *execSender) Close() error {
+	return nil
+}
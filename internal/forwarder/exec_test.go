@@ -0,0 +1,86 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+func TestExecSender_PipesEventToStdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.json")
+	s, err := NewSender("bot", config.Target{
+		URL:  "exec:///bin/sh",
+		Exec: &config.ExecOptions{Args: []string{"-c", `cat > "$0"; echo "$HOOKBUSTER_RESOURCE:$HOOKBUSTER_EVENT" >> "$0"`, out}},
+	})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	raw, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read command output: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("output = %q, want JSON line and env line", raw)
+	}
+	var decoded config.WebhookEvent
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("stdin was not a JSON event: %v", err)
+	}
+	if lines[1] != "messages:created" {
+		t.Errorf("env = %q, want messages:created", lines[1])
+	}
+}
+
+func TestExecSender_NonZeroExitFails(t *testing.T) {
+	s, err := NewSender("bot", config.Target{
+		URL:  "exec:///bin/sh",
+		Exec: &config.ExecOptions{Args: []string{"-c", "echo boom >&2; exit 3"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+
+	err = s.Send(config.WebhookEvent{Resource: "rooms", Event: "updated"})
+	if err == nil {
+		t.Fatal("Send() should fail on non-zero exit")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error = %q, want stderr included", err.Error())
+	}
+}
+
+func TestExecSender_Timeout(t *testing.T) {
+	s, err := NewSender("bot", config.Target{
+		URL:  "exec:///bin/sh",
+		Exec: &config.ExecOptions{Args: []string{"-c", "sleep 5"}, Timeout: "100ms"},
+	})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+
+	err = s.Send(config.WebhookEvent{Resource: "rooms", Event: "updated"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Send() error = %v, want timeout", err)
+	}
+}
+
+func TestExecSender_ProbeMissingCommand(t *testing.T) {
+	s, err := NewSender("bot", config.Target{URL: "exec:///nonexistent/hookbuster-handler"})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail for a missing command")
+	}
+}
@@ -0,0 +1,283 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// rotatedTimeFormat is the timestamp inserted into rotated file names. It
+// sorts lexically in time order.
+const rotatedTimeFormat = "20060102T150405.000"
+
+// fileSender appends events as JSON Lines to a file, rotating it by size
+// and/or age. Rotated files are renamed to <name>-<timestamp><ext> and
+// gzipped in the background.
+type fileSender struct {
+	mu          sync.Mutex
+	path        string
+	maxSize     int64
+	rotateEvery time.Duration
+	maxBackups  int
+	compress    bool
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// now is replaced in tests to drive time-based rotation.
+	now func() time.Time
+
+	// compressing tracks background gzip jobs so Close can wait for them;
+	// housekeeping serialises them so pruning never races a compression.
+	compressing  sync.WaitGroup
+	housekeeping sync.Mutex
+}
+
+func newFileSender(_ string, target config.Target, u *url.URL) (Sender, error) {
+	path := config.TargetPath(u)
+	if path == "" {
+		return nil, fmt.Errorf("file target %q must include a path", target.URL)
+	}
+
+	s := &fileSender{
+		path:     filepath.Clean(path),
+		compress: true,
+		now:      time.Now,
+	}
+
+	if f := target.File; f != nil {
+		s.maxSize = int64(f.MaxSizeMB) * 1024 * 1024
+		s.maxBackups = f.MaxBackups
+		if f.Compress != nil {
+			s.compress = *f.Compress
+		}
+		if f.RotateEvery != "" {
+			d, err := time.ParseDuration(f.RotateEvery)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file rotate_every %q: %w", f.RotateEvery, err)
+			}
+			s.rotateEvery = d
+		}
+	}
+
+	return s, nil
+}
+
+// Send appends the event as one compact JSON line, rotating first if the
+// line would push the file past its size limit or the file is too old.
+func (s *fileSender) Send(event config.WebhookEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.openLocked(); err != nil {
+		return err
+	}
+	if s.shouldRotateLocked(int64(len(line))) {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Probe checks the file can be opened for appending.
+func (s *fileSender) Probe() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openLocked()
+}
+
+// Close closes the file and waits for pending compression.
+func (s *fileSender) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	s.mu.Unlock()
+
+	s.compressing.Wait()
+	return err
+}
+
+// openLocked opens (or creates) the file for appending if it is not open.
+// The age of an existing file is taken from its modification time so
+// time-based rotation survives restarts.
+func (s *fileSender) openLocked() error {
+	if s.file != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", s.path, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+
+	s.file = f
+	s.size = info.Size()
+	s.openedAt = s.now()
+	if s.size > 0 {
+		s.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (s *fileSender) shouldRotateLocked(next int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.maxSize > 0 && s.size+next > s.maxSize {
+		return true
+	}
+	return s.rotateEvery > 0 && s.now().Sub(s.openedAt) >= s.rotateEvery
+}
+
+// rotateLocked renames the current file aside, reopens a fresh one and
+// hands the old one to the background compressor.
+func (s *fileSender) rotateLocked() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s for rotation: %w", s.path, err)
+	}
+	s.file = nil
+
+	rotated := s.rotatedName(s.now())
+	if err := os.Rename(s.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", s.path, err)
+	}
+
+	if err := s.openLocked(); err != nil {
+		return err
+	}
+
+	s.compressing.Add(1)
+	go func() {
+		defer s.compressing.Done()
+		s.housekeeping.Lock()
+		defer s.housekeeping.Unlock()
+		if s.compress {
+			if err := gzipFile(rotated); err != nil {
+				display.Println(display.Error(fmt.Sprintf("file target: %s", err.Error())))
+			}
+		}
+		s.pruneBackups()
+	}()
+	return nil
+}
+
+// rotatedName returns <dir>/<name>-<timestamp><ext> for the active path.
+// If two rotations land in the same millisecond the timestamp is bumped so
+// names stay unique and in order.
+func (s *fileSender) rotatedName(t time.Time) string {
+	ext := filepath.Ext(s.path)
+	base := strings.TrimSuffix(s.path, ext)
+	for {
+		name := fmt.Sprintf("%s-%s%s", base, t.UTC().Format(rotatedTimeFormat), ext)
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// pruneBackups deletes the oldest rotated files beyond maxBackups.
+func (s *fileSender) pruneBackups() {
+	if s.maxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(s.path)
+	base := strings.TrimSuffix(s.path, ext)
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return
+	}
+	// A plain file and its .gz can briefly coexist while compressing;
+	// count each rotation once.
+	seen := make(map[string]bool)
+	var backups []string
+	for _, m := range matches {
+		key := strings.TrimSuffix(m, ".gz")
+		if !seen[key] {
+			seen[key] = true
+			backups = append(backups, key)
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > s.maxBackups {
+		_ = os.Remove(backups[0])
+		_ = os.Remove(backups[0] + ".gz")
+		backups = backups[1:]
+	}
+}
+
+// gzipFile compresses path to path.gz and removes the original.
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for compression: %w", path, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s.gz: %w", path, err)
+	}
+
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
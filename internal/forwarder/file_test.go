@@ -0,0 +1,181 @@
+package forwarder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// helper: file sender writing to dir/events.jsonl
+func newTestFileSender(t *testing.T, dir string, opts *config.FileOptions) *fileSender {
+	t.Helper()
+	s, err := NewSender("bot", config.Target{URL: "file://" + filepath.Join(dir, "events.jsonl"), File: opts})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s.(*fileSender)
+}
+
+// helper: count JSON lines in a plain or gzipped file
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var scanner *bufio.Scanner
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("failed to read gzip %s: %v", path, err)
+		}
+		scanner = bufio.NewScanner(zr)
+	} else {
+		scanner = bufio.NewScanner(f)
+	}
+
+	n := 0
+	for scanner.Scan() {
+		var event config.WebhookEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid JSON line in %s: %v", path, err)
+		}
+		n++
+	}
+	return n
+}
+
+func TestTargetPath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"file:///var/log/events.jsonl", "/var/log/events.jsonl"},
+		{"file://logs/events.jsonl", "logs/events.jsonl"},
+		{"file://./events.jsonl", "events.jsonl"},
+		{"exec://handler", "handler"},
+		{"exec:///usr/local/bin/handler", "/usr/local/bin/handler"},
+	}
+	for _, tt := range tests {
+		s, err := NewSender("", config.Target{URL: tt.url})
+		if err != nil {
+			t.Fatalf("NewSender(%q) error: %v", tt.url, err)
+		}
+		var got string
+		switch v := s.(type) {
+		case *fileSender:
+			got = v.path
+		case *execSender:
+			got = v.command
+		}
+		if got != tt.want {
+			t.Errorf("path for %q = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestFileSender_AppendsJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestFileSender(t, dir, nil)
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}}
+	for i := 0; i < 3; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	if n := countLines(t, filepath.Join(dir, "events.jsonl")); n != 3 {
+		t.Errorf("lines = %d, want 3", n)
+	}
+}
+
+func TestFileSender_SizeRotationCompresses(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestFileSender(t, dir, &config.FileOptions{MaxSizeMB: 1})
+	// Shrink the limit so a handful of events trigger rotation
+	s.maxSize = 200
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}}
+	for i := 0; i < 6; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	rotated, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl.gz"))
+	if len(rotated) == 0 {
+		t.Fatal("expected gzipped rotated files")
+	}
+	if plain, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl")); len(plain) != 0 {
+		t.Errorf("uncompressed rotated files left behind: %v", plain)
+	}
+
+	total := countLines(t, filepath.Join(dir, "events.jsonl"))
+	for _, r := range rotated {
+		total += countLines(t, r)
+	}
+	if total != 6 {
+		t.Errorf("total lines across files = %d, want 6", total)
+	}
+}
+
+func TestFileSender_TimeRotationAndMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	compress := false
+	s := newTestFileSender(t, dir, &config.FileOptions{RotateEvery: "1h", MaxBackups: 2, Compress: &compress})
+
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	for i := 0; i < 5; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+		clock = clock.Add(time.Hour)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	rotated, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	if len(rotated) != 2 {
+		t.Fatalf("rotated files = %v, want 2 kept", rotated)
+	}
+	if !strings.Contains(rotated[1], "20260101T040000.000") {
+		t.Errorf("newest backup = %s, want the 04:00 rotation", rotated[1])
+	}
+	if n := countLines(t, filepath.Join(dir, "events.jsonl")); n != 1 {
+		t.Errorf("active file lines = %d, want 1", n)
+	}
+}
+
+func TestFileSender_ProbeUnwritable(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "not-a-dir")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSender("bot", config.Target{URL: "file://" + filepath.Join(blocker, "events.jsonl")})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail when the directory cannot be created")
+	}
+}
@@ -40,14 +40,14 @@ func ForwardToURL(targetURL string, event config.WebhookEvent) error {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		fmt.Println(display.Error(fmt.Sprintf("forward error: %s", err.Error())))
+		display.Println(display.Error(fmt.Sprintf("forward error: %s", err.Error())))
 		return err
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("statusCode: %d\n", resp.StatusCode)
-	fmt.Println(display.Info(fmt.Sprintf("event forwarded to %s", targetURL)))
-	fmt.Println(display.Info(string(data)))
+	display.Println(display.Info(fmt.Sprintf("statusCode: %d", resp.StatusCode)))
+	display.Println(display.Info(fmt.Sprintf("event forwarded to %s", targetURL)))
+	display.Println(display.Info(string(data)))
 
 	return nil
 }
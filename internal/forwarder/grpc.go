@@ -0,0 +1,241 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	hookbusterv1 "github.com/tejzpr/webex-go-hookbuster/api/hookbuster/v1"
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// grpcAckTimeout is how long Send waits for the receiver to ack an event.
+const grpcAckTimeout = healthCheckTimeout
+
+// errGRPCStreamClosed is returned for events still waiting on an ack when
+// the Publish stream is closed.
+var errGRPCStreamClosed = errors.New("grpc publish stream closed")
+
+// grpcSender pushes events over a single bidirectional Publish stream and
+// waits for the receiver's ack for each one. The stream is opened lazily
+// and dropped when it breaks, so the next Send reopens it; gRPC itself
+// redials the underlying connection.
+type grpcSender struct {
+	addr     string
+	pipeline string
+	token    string
+	conn     *grpc.ClientConn
+	client   hookbusterv1.EventStreamClient
+
+	// sendMu serialises stream.Send, which is not safe for concurrent use.
+	// It is separate from mu so the receive loop can deliver acks while a
+	// Send is blocked on flow control.
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	stream  hookbusterv1.EventStream_PublishClient
+	cancel  context.CancelFunc
+	seq     uint64
+	pending map[uint64]chan error
+}
+
+func newGRPCSender(pipeline string, target config.Target, u *url.URL) (Sender, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("grpc target %q must be %s://host:port", target.URL, u.Scheme)
+	}
+
+	creds := insecure.NewCredentials()
+	var token string
+	var tlsCfg *tls.Config
+	if g := target.GRPC; g != nil {
+		if g.TokenEnv != "" {
+			token = os.Getenv(g.TokenEnv)
+		}
+		var err error
+		if tlsCfg, err = buildTLSConfig(g.TLS); err != nil {
+			return nil, err
+		}
+	}
+	if u.Scheme == "grpcs" {
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
+	conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid grpc target %q: %w", target.URL, err)
+	}
+
+	return &grpcSender{
+		addr:     u.Host,
+		pipeline: pipeline,
+		token:    token,
+		conn:     conn,
+		client:   hookbusterv1.NewEventStreamClient(conn),
+		pending:  make(map[uint64]chan error),
+	}, nil
+}
+
+// Send pushes the event on the Publish stream and blocks until the
+// receiver acks it, rejects it, or grpcAckTimeout passes.
+func (s *grpcSender) Send(event config.WebhookEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	stream, seq, ackCh, err := s.reserve()
+	if err != nil {
+		return err
+	}
+
+	s.sendMu.Lock()
+	err = stream.Send(&hookbusterv1.Event{
+		Sequence:  seq,
+		Pipeline:  routingPipeline(s.pipeline),
+		Resource:  event.Resource,
+		Event:     event.Event,
+		Timestamp: event.Timestamp,
+		Data:      data,
+	})
+	s.sendMu.Unlock()
+	if err != nil {
+		s.closeStream(stream, err)
+		return fmt.Errorf("grpc send to %s failed: %w", s.addr, err)
+	}
+
+	timer := time.NewTimer(grpcAckTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-ackCh:
+		if err != nil {
+			return fmt.Errorf("grpc target %s: %w", s.addr, err)
+		}
+	case <-timer.C:
+		s.mu.Lock()
+		delete(s.pending, seq)
+		s.mu.Unlock()
+		return fmt.Errorf("grpc target %s did not ack event %d within %s", s.addr, seq, grpcAckTimeout)
+	}
+
+	display.Println(display.Info(fmt.Sprintf("event forwarded to grpc receiver %s (seq %d)", s.addr, seq)))
+	return nil
+}
+
+// Probe calls Ping. A receiver that does not implement Ping answered the
+// call, so it still counts as reachable.
+func (s *grpcSender) Probe() error {
+	ctx, cancel := context.WithTimeout(s.outgoing(context.Background()), healthCheckTimeout)
+	defer cancel()
+
+	_, err := s.client.Ping(ctx, &hookbusterv1.PingRequest{})
+	if err != nil && status.Code(err) != codes.Unimplemented {
+		return fmt.Errorf("grpc ping to %s failed: %w", s.addr, err)
+	}
+	return nil
+}
+
+// Close ends the Publish stream and closes the connection.
+func (s *grpcSender) Close() error {
+	s.mu.Lock()
+	stream := s.stream
+	s.mu.Unlock()
+	if stream != nil {
+		s.closeStream(stream, errGRPCStreamClosed)
+	}
+	return s.conn.Close()
+}
+
+// reserve returns the open stream (opening one if needed) together with a
+// new sequence number and the channel its ack will be delivered on.
+func (s *grpcSender) reserve() (hookbusterv1.EventStream_PublishClient, uint64, chan error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		ctx, cancel := context.WithCancel(s.outgoing(context.Background()))
+		stream, err := s.client.Publish(ctx)
+		if err != nil {
+			cancel()
+			return nil, 0, nil, fmt.Errorf("failed to open grpc stream to %s: %w", s.addr, err)
+		}
+		s.stream = stream
+		s.cancel = cancel
+		go s.receiveAcks(stream)
+	}
+
+	s.seq++
+	ackCh := make(chan error, 1)
+	s.pending[s.seq] = ackCh
+	return s.stream, s.seq, ackCh, nil
+}
+
+// receiveAcks delivers acks to waiting Sends until the stream ends.
+func (s *grpcSender) receiveAcks(stream hookbusterv1.EventStream_PublishClient) {
+	for {
+		ack, err := stream.Recv()
+		if err != nil {
+			s.closeStream(stream, err)
+			return
+		}
+
+		s.mu.Lock()
+		ackCh, ok := s.pending[ack.GetSequence()]
+		delete(s.pending, ack.GetSequence())
+		s.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if ack.GetError() != "" {
+			ackCh <- fmt.Errorf("receiver rejected event: %s", ack.GetError())
+		} else {
+			ackCh <- nil
+		}
+	}
+}
+
+// closeStream tears down stream if it is still the current one and fails
+// every event waiting on an ack from it.
+func (s *grpcSender) closeStream(stream hookbusterv1.EventStream_PublishClient, cause error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream != stream {
+		return
+	}
+
+	s.cancel()
+	s.stream = nil
+	s.cancel = nil
+	for seq, ackCh := range s.pending {
+		ackCh <- fmt.Errorf("%w: %v", errGRPCStreamClosed, cause)
+		delete(s.pending, seq)
+	}
+}
+
+// outgoing attaches the bearer token, when configured, to ctx.
+func (s *grpcSender) outgoing(ctx context.Context) context.Context {
+	if s.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.token)
+}
@@ -0,0 +1,240 @@
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	hookbusterv1 "github.com/tejzpr/webex-go-hookbuster/api/hookbuster/v1"
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// testReceiver is an EventStream server that records events and acks them,
+// rejecting all of them when reject is set.
+type testReceiver struct {
+	hookbusterv1.UnimplementedEventStreamServer
+
+	mu     sync.Mutex
+	events []*hookbusterv1.Event
+	auth   []string
+	reject string
+}
+
+func (r *testReceiver) Publish(stream hookbusterv1.EventStream_PublishServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	r.mu.Lock()
+	r.auth = md.Get("authorization")
+	r.mu.Unlock()
+
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		r.events = append(r.events, ev)
+		reject := r.reject
+		r.mu.Unlock()
+
+		if err := stream.Send(&hookbusterv1.Ack{Sequence: ev.GetSequence(), Error: reject}); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *testReceiver) Ping(context.Context, *hookbusterv1.PingRequest) (*hookbusterv1.PingResponse, error) {
+	return &hookbusterv1.PingResponse{}, nil
+}
+
+// helper: serve r on a random local port and return the grpc:// url
+func newTestReceiver(t *testing.T, r hookbusterv1.EventStreamServer) (string, *grpc.Server) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	hookbusterv1.RegisterEventStreamServer(srv, r)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return "grpc://" + lis.Addr().String(), srv
+}
+
+func TestGRPCSender_SendAcked(t *testing.T) {
+	recv := &testReceiver{}
+	url, _ := newTestReceiver(t, recv)
+
+	t.Setenv("GRPC_RECEIVER_TOKEN", "s3cret")
+	s, err := NewSender("bot", config.Target{URL: url, GRPC: &config.GRPCOptions{TokenEnv: "GRPC_RECEIVER_TOKEN"}})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{
+		Resource:  "messages",
+		Event:     "created",
+		Data:      map[string]interface{}{"id": "msg-1"},
+		Timestamp: 1700000000000,
+	}
+	for i := 0; i < 3; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+	if len(recv.events) != 3 {
+		t.Fatalf("received %d events, want 3", len(recv.events))
+	}
+	got := recv.events[2]
+	if got.GetSequence() != 3 || got.GetPipeline() != "bot" || got.GetResource() != "messages" || got.GetEvent() != "created" {
+		t.Errorf("event = %+v, want seq 3 bot messages:created", got)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(got.GetData(), &data); err != nil || data["id"] != "msg-1" {
+		t.Errorf("data = %s, want JSON with id msg-1", got.GetData())
+	}
+	if len(recv.auth) != 1 || recv.auth[0] != "Bearer s3cret" {
+		t.Errorf("authorization = %v, want bearer token", recv.auth)
+	}
+}
+
+func TestGRPCSender_ConcurrentSends(t *testing.T) {
+	recv := &testReceiver{}
+	url, _ := newTestReceiver(t, recv)
+
+	s, err := NewSender("bot", config.Target{URL: url})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.Send(event)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Send() error: %v", err)
+		}
+	}
+}
+
+func TestGRPCSender_RejectedAckFails(t *testing.T) {
+	recv := &testReceiver{reject: "queue full"}
+	url, _ := newTestReceiver(t, recv)
+
+	b := newTestBalancer(t, "bot", []config.Target{{URL: url}})
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	for i := 0; i < maxConsecutiveFailures; i++ {
+		_ = b.Forward(event)
+	}
+	if b.HealthyCount() != 0 {
+		t.Fatal("grpc target rejecting every event should be marked unhealthy")
+	}
+
+	recv.mu.Lock()
+	recv.reject = ""
+	recv.mu.Unlock()
+	b.probeUnhealthy()
+	if b.HealthyCount() != 1 {
+		t.Error("grpc target should recover after a successful Ping")
+	}
+}
+
+func TestGRPCSender_ReopensStreamAfterServerRestart(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	srv := grpc.NewServer()
+	hookbusterv1.RegisterEventStreamServer(srv, &testReceiver{})
+	go func() { _ = srv.Serve(lis) }()
+
+	s, err := NewSender("bot", config.Target{URL: "grpc://" + addr})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	srv.Stop()
+	if err := s.Send(event); err == nil {
+		t.Fatal("Send() should fail while the receiver is down")
+	}
+
+	lis, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("could not rebind %s: %v", addr, err)
+	}
+	srv = grpc.NewServer()
+	hookbusterv1.RegisterEventStreamServer(srv, &testReceiver{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	if err := s.Probe(); err != nil {
+		t.Fatalf("Probe() error after restart: %v", err)
+	}
+	if err := s.Send(event); err != nil {
+		t.Errorf("Send() after restart error: %v", err)
+	}
+}
+
+// pingless implements Publish only, like a receiver built before Ping existed.
+type pingless struct {
+	hookbusterv1.UnimplementedEventStreamServer
+}
+
+func TestGRPCSender_ProbeUnimplementedPing(t *testing.T) {
+	url, _ := newTestReceiver(t, &pingless{})
+
+	s, err := NewSender("bot", config.Target{URL: url})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	if err := s.Probe(); err != nil {
+		t.Errorf("Probe() = %v, want nil when Ping is %s", err, codes.Unimplemented)
+	}
+}
+
+func TestGRPCSender_ProbeUnreachable(t *testing.T) {
+	s, err := NewSender("bot", config.Target{URL: "grpc://127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail for unreachable receiver")
+	}
+}
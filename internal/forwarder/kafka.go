@@ -0,0 +1,241 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// kafkaAcks maps config acks values to sarama's RequiredAcks.
+var kafkaAcks = map[string]sarama.RequiredAcks{
+	config.KafkaAcksNone:   sarama.NoResponse,
+	config.KafkaAcksLeader: sarama.WaitForLocal,
+	config.KafkaAcksAll:    sarama.WaitForAll,
+}
+
+// kafkaCompression maps config compression values to sarama codecs.
+var kafkaCompression = map[string]sarama.CompressionCodec{
+	"none":   sarama.CompressionNone,
+	"gzip":   sarama.CompressionGZIP,
+	"snappy": sarama.CompressionSnappy,
+	"lz4":    sarama.CompressionLZ4,
+	"zstd":   sarama.CompressionZSTD,
+}
+
+// kafkaSender publishes events to a Kafka topic. The producer is created
+// lazily and dropped after a failed send, so Probe can reconnect once the
+// brokers come back.
+type kafkaSender struct {
+	mu       sync.Mutex
+	brokers  []string
+	topic    string
+	cfg      *sarama.Config
+	producer sarama.SyncProducer
+}
+
+func newKafkaSender(_ string, target config.Target, u *url.URL) (Sender, error) {
+	brokers := strings.Split(u.Host, ",")
+	topic := strings.Trim(u.Path, "/")
+	if u.Host == "" || topic == "" {
+		return nil, fmt.Errorf("kafka target %q must be kafka://broker[,broker]/topic", target.URL)
+	}
+
+	cfg, err := newKafkaConfig(target.Kafka)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaSender{
+		brokers: brokers,
+		topic:   topic,
+		cfg:     cfg,
+	}, nil
+}
+
+// newKafkaConfig builds a sarama config from the target's kafka options.
+func newKafkaConfig(opts *config.KafkaOptions) (*sarama.Config, error) {
+	cfg := sarama.NewConfig()
+	cfg.ClientID = "hookbuster"
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Net.DialTimeout = healthCheckTimeout
+	cfg.Metadata.Retry.Max = 1
+
+	if opts == nil {
+		return cfg, nil
+	}
+
+	if opts.Acks != "" {
+		acks, ok := kafkaAcks[opts.Acks]
+		if !ok {
+			return nil, fmt.Errorf("unknown kafka acks %q", opts.Acks)
+		}
+		cfg.Producer.RequiredAcks = acks
+	}
+
+	if opts.Compression != "" {
+		codec, ok := kafkaCompression[opts.Compression]
+		if !ok {
+			return nil, fmt.Errorf("unknown kafka compression %q", opts.Compression)
+		}
+		cfg.Producer.Compression = codec
+	}
+
+	tlsCfg, err := buildTLSConfig(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsCfg
+	}
+
+	if opts.SASL != nil {
+		configureKafkaSASL(cfg, opts.SASL)
+	}
+
+	return cfg, nil
+}
+
+// configureKafkaSASL enables SASL on cfg, reading the password from the
+// configured env var.
+func configureKafkaSASL(cfg *sarama.Config, sasl *config.SASLOptions) {
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.User = sasl.Username
+	cfg.Net.SASL.Password = os.Getenv(sasl.PasswordEnv)
+
+	switch sasl.Mechanism {
+	case config.SASLScramSHA256:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{hash: scram.HashGeneratorFcn(sha256.New)}
+		}
+	case config.SASLScramSHA512:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{hash: scram.HashGeneratorFcn(sha512.New)}
+		}
+	default:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	}
+}
+
+// Send publishes the event as compact JSON, keyed by room ID so all events
+// for a room land on the same partition in order.
+func (s *kafkaSender) Send(event config.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	producer, err := s.getProducer()
+	if err != nil {
+		return err
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(payload),
+	}
+	if key := kafkaKey(event); key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+
+	partition, offset, err := producer.SendMessage(msg)
+	if err != nil {
+		s.resetProducer()
+		return fmt.Errorf("kafka publish to %s failed: %w", s.topic, err)
+	}
+
+	display.Println(display.Info(fmt.Sprintf("event forwarded to kafka topic %s (partition %d, offset %d)",
+		s.topic, partition, offset)))
+	return nil
+}
+
+// Probe reconnects the producer if it was dropped after a failure.
+func (s *kafkaSender) Probe() error {
+	_, err := s.getProducer()
+	return err
+}
+
+// Close shuts down the producer, flushing any buffered messages.
+func (s *kafkaSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.producer == nil {
+		return nil
+	}
+	err := s.producer.Close()
+	s.producer = nil
+	return err
+}
+
+// getProducer returns the current producer, connecting if needed.
+func (s *kafkaSender) getProducer() (sarama.SyncProducer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.producer != nil {
+		return s.producer, nil
+	}
+
+	producer, err := sarama.NewSyncProducer(s.brokers, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to kafka brokers %s: %w", strings.Join(s.brokers, ","), err)
+	}
+	s.producer = producer
+	return producer, nil
+}
+
+// resetProducer closes and drops the producer so the next Send or Probe
+// starts from a fresh connection.
+func (s *kafkaSender) resetProducer() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.producer != nil {
+		_ = s.producer.Close()
+		s.producer = nil
+	}
+}
+
+// kafkaKey returns the partition key for an event: its roomId when present.
+func kafkaKey(event config.WebhookEvent) string {
+	return eventDataString(event, "roomId")
+}
+
+// scramClient adapts xdg-go/scram to sarama's SCRAMClient interface.
+type scramClient struct {
+	hash         scram.HashGeneratorFcn
+	conversation *scram.ClientConversation
+}
+
+func (c *scramClient) Begin(userName, password, authzID string) error {
+	client, err := c.hash.NewClient(userName, password, authzID)
+	if err != nil {
+		return err
+	}
+	c.conversation = client.NewConversation()
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	return c.conversation.Step(challenge)
+}
+
+func (c *scramClient) Done() bool {
+	return c.conversation.Done()
+}
@@ -0,0 +1,211 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// helper: start an in-process fake broker that leads every partition of topic
+func newFakeKafkaBroker(t *testing.T, topic string) *sarama.MockBroker {
+	t.Helper()
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		// Advertise the produce (0) and metadata (3) APIs the producer needs
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t).SetApiKeys([]sarama.ApiVersionsResponseKey{
+			{ApiKey: 0, MinVersion: 0, MaxVersion: 9},
+			{ApiKey: 3, MinVersion: 0, MaxVersion: 12},
+		}),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+	return broker
+}
+
+// helper: new kafka sender that we always close in cleanup
+func newTestKafkaSender(t *testing.T, target config.Target) *kafkaSender {
+	t.Helper()
+	s, err := NewSender("test", target)
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s.(*kafkaSender)
+}
+
+func TestNewSender_KafkaParsesBrokersAndTopic(t *testing.T) {
+	s := newTestKafkaSender(t, config.Target{URL: "kafka://b1:9092,b2:9092/webex-events"})
+
+	if len(s.brokers) != 2 || s.brokers[0] != "b1:9092" || s.brokers[1] != "b2:9092" {
+		t.Errorf("brokers = %v, want [b1:9092 b2:9092]", s.brokers)
+	}
+	if s.topic != "webex-events" {
+		t.Errorf("topic = %q, want %q", s.topic, "webex-events")
+	}
+}
+
+func TestNewSender_KafkaRequiresTopic(t *testing.T) {
+	if _, err := NewSender("test", config.Target{URL: "kafka://b1:9092"}); err == nil {
+		t.Fatal("expected error for kafka url without topic")
+	}
+}
+
+func TestNewKafkaConfig_Options(t *testing.T) {
+	cfg, err := newKafkaConfig(&config.KafkaOptions{
+		Acks:        config.KafkaAcksLeader,
+		Compression: "zstd",
+		SASL: &config.SASLOptions{
+			Mechanism:   config.SASLScramSHA512,
+			Username:    "hookbuster",
+			PasswordEnv: "TEST_KAFKA_PASSWORD",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newKafkaConfig() error: %v", err)
+	}
+
+	if cfg.Producer.RequiredAcks != sarama.WaitForLocal {
+		t.Errorf("RequiredAcks = %v, want WaitForLocal", cfg.Producer.RequiredAcks)
+	}
+	if cfg.Producer.Compression != sarama.CompressionZSTD {
+		t.Errorf("Compression = %v, want zstd", cfg.Producer.Compression)
+	}
+	if !cfg.Net.SASL.Enable || cfg.Net.SASL.Mechanism != sarama.SASLTypeSCRAMSHA512 {
+		t.Errorf("SASL = %v/%v, want enabled SCRAM-SHA-512", cfg.Net.SASL.Enable, cfg.Net.SASL.Mechanism)
+	}
+	if cfg.Net.SASL.SCRAMClientGeneratorFunc == nil {
+		t.Error("SCRAM client generator should be set")
+	}
+}
+
+func TestNewKafkaConfig_DefaultsToAcksAll(t *testing.T) {
+	cfg, err := newKafkaConfig(nil)
+	if err != nil {
+		t.Fatalf("newKafkaConfig() error: %v", err)
+	}
+	if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+		t.Errorf("RequiredAcks = %v, want WaitForAll", cfg.Producer.RequiredAcks)
+	}
+	if cfg.Net.SASL.Enable || cfg.Net.TLS.Enable {
+		t.Error("SASL and TLS should be disabled by default")
+	}
+}
+
+func TestKafkaKey(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"room id", map[string]interface{}{"roomId": "room-1"}, "room-1"},
+		{"no room id", map[string]interface{}{"id": "msg-1"}, ""},
+		{"non-map data", "raw", ""},
+	}
+
+	for _, tt := range tests {
+		got := kafkaKey(config.WebhookEvent{Data: tt.data})
+		if got != tt.want {
+			t.Errorf("%s: kafkaKey() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKafkaSender_PublishesKeyedJSON(t *testing.T) {
+	s := newTestKafkaSender(t, config.Target{URL: "kafka://unused:9092/webex-events"})
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "webex-events" {
+			return fmt.Errorf("topic = %q", msg.Topic)
+		}
+		key, _ := msg.Key.Encode()
+		if string(key) != "room-1" {
+			return fmt.Errorf("key = %q, want room-1", key)
+		}
+		value, _ := msg.Value.Encode()
+		var decoded config.WebhookEvent
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return err
+		}
+		if decoded.Resource != "messages" || decoded.Event != "created" {
+			return fmt.Errorf("decoded %s:%s", decoded.Resource, decoded.Event)
+		}
+		return nil
+	})
+	s.producer = producer
+
+	event := config.WebhookEvent{
+		Resource: "messages",
+		Event:    "created",
+		Data:     map[string]interface{}{"roomId": "room-1"},
+	}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+}
+
+func TestKafkaSender_FailureDropsProducer(t *testing.T) {
+	s := newTestKafkaSender(t, config.Target{URL: "kafka://127.0.0.1:1/webex-events"})
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+	s.producer = producer
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{}}
+	if err := s.Send(event); err == nil {
+		t.Fatal("Send() should return error when the broker rejects the message")
+	}
+	if s.producer != nil {
+		t.Error("producer should be dropped after a failed send")
+	}
+}
+
+func TestKafkaSender_FakeBroker(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "webex-events")
+	s := newTestKafkaSender(t, config.Target{URL: "kafka://" + broker.Addr() + "/webex-events"})
+
+	event := config.WebhookEvent{
+		Resource: "messages",
+		Event:    "created",
+		Data:     map[string]interface{}{"roomId": "room-1"},
+	}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	produced := false
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced = true
+		}
+	}
+	if !produced {
+		t.Error("fake broker should have received a ProduceRequest")
+	}
+}
+
+func TestKafkaSender_ProbeUnreachable(t *testing.T) {
+	s := newTestKafkaSender(t, config.Target{URL: "kafka://127.0.0.1:1/webex-events"})
+
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail for unreachable brokers")
+	}
+}
+
+func TestBalancer_KafkaTarget(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "webex-events")
+	b := newTestBalancer(t, "test", targetsFromURLs("kafka://"+broker.Addr()+"/webex-events"))
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "created", Data: map[string]interface{}{}}
+	if err := b.Forward(event); err != nil {
+		t.Fatalf("Forward() error: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// natsSubjectReplacer strips characters that would split or wildcard a
+// NATS subject token.
+var natsSubjectReplacer = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_")
+
+// natsSender publishes events to NATS subjects. With JetStream enabled each
+// publish waits for a stream ack, so a missing stream or a lost ack counts
+// as a failed delivery for retry and health tracking.
+type natsSender struct {
+	mu       sync.Mutex
+	servers  string
+	pipeline string
+	prefix   string
+	opts     []nats.Option
+	useJS    bool
+	conn     *nats.Conn
+	js       jetstream.JetStream
+}
+
+func newNATSSender(pipeline string, target config.Target, u *url.URL) (Sender, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("nats target %q must be nats://host[:port][,host[:port]]", target.URL)
+	}
+
+	hosts := strings.Split(u.Host, ",")
+	servers := make([]string, len(hosts))
+	for i, h := range hosts {
+		servers[i] = "nats://" + h
+	}
+
+	opts := []nats.Option{
+		nats.Name("hookbuster"),
+		nats.Timeout(healthCheckTimeout),
+		nats.MaxReconnects(-1),
+	}
+
+	prefix := config.DefaultNATSSubjectPrefix
+	useJS := false
+	if n := target.NATS; n != nil {
+		if n.SubjectPrefix != "" {
+			prefix = n.SubjectPrefix
+		}
+		useJS = n.JetStream
+
+		authOpts, err := natsAuthOptions(n)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, authOpts...)
+	}
+
+	return &natsSender{
+		servers:  strings.Join(servers, ","),
+		pipeline: pipeline,
+		prefix:   prefix,
+		opts:     opts,
+		useJS:    useJS,
+	}, nil
+}
+
+// natsAuthOptions converts credentials and TLS settings into nats options.
+func natsAuthOptions(n *config.NATSOptions) ([]nats.Option, error) {
+	var opts []nats.Option
+
+	if n.CredsFile != "" {
+		opts = append(opts, nats.UserCredentials(n.CredsFile))
+	}
+	if n.TokenEnv != "" {
+		opts = append(opts, nats.Token(os.Getenv(n.TokenEnv)))
+	}
+	if n.Username != "" {
+		opts = append(opts, nats.UserInfo(n.Username, os.Getenv(n.PasswordEnv)))
+	}
+
+	tlsCfg, err := buildTLSConfig(n.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, nats.Secure(tlsCfg))
+	}
+
+	return opts, nil
+}
+
+// Send publishes the event as compact JSON to its subject.
+func (s *natsSender) Send(event config.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	conn, js, err := s.connect()
+	if err != nil {
+		return err
+	}
+
+	subject := natsSubject(s.prefix, s.pipeline, event)
+	msg := nats.NewMsg(subject)
+	msg.Data = payload
+	msg.Header.Set("Content-Type", "application/json")
+
+	if js != nil {
+		// Deduplicate redeliveries of the same activity within the stream window
+		if id := eventDataString(event, "id"); id != "" {
+			msg.Header.Set(jetstream.MsgIDHeader, fmt.Sprintf("%s:%s:%s", event.Resource, event.Event, id))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+		ack, err := js.PublishMsg(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("jetstream publish to %s failed: %w", subject, err)
+		}
+		display.Println(display.Info(fmt.Sprintf("event forwarded to nats subject %s (stream %s, seq %d)",
+			subject, ack.Stream, ack.Sequence)))
+		return nil
+	}
+
+	if err := conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("nats publish to %s failed: %w", subject, err)
+	}
+	// Flush so an unreachable server surfaces as a failed delivery
+	if err := conn.FlushTimeout(healthCheckTimeout); err != nil {
+		return fmt.Errorf("nats publish to %s failed: %w", subject, err)
+	}
+	display.Println(display.Info(fmt.Sprintf("event forwarded to nats subject %s", subject)))
+	return nil
+}
+
+// Probe connects if needed and checks the connection is live.
+func (s *natsSender) Probe() error {
+	conn, _, err := s.connect()
+	if err != nil {
+		return err
+	}
+	if !conn.IsConnected() {
+		return fmt.Errorf("nats connection to %s is %s", s.servers, conn.Status())
+	}
+	return nil
+}
+
+// Close drains pending publishes and closes the connection.
+func (s *natsSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Drain()
+	s.conn = nil
+	s.js = nil
+	return err
+}
+
+// connect returns the live connection (and JetStream context when enabled),
+// dialling on first use. After that nats.go reconnects on its own.
+func (s *natsSender) connect() (*nats.Conn, jetstream.JetStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn, s.js, nil
+	}
+
+	conn, err := nats.Connect(s.servers, s.opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to nats %s: %w", s.servers, err)
+	}
+
+	var js jetstream.JetStream
+	if s.useJS {
+		js, err = jetstream.New(conn)
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("failed to create jetstream context: %w", err)
+		}
+	}
+
+	s.conn = conn
+	s.js = js
+	return conn, js, nil
+}
+
+// natsSubject builds "<prefix>.<pipeline>.<resource>.<event>".
+func natsSubject(prefix, pipeline string, event config.WebhookEvent) string {
+	return strings.Join([]string{
+		prefix,
+		natsSubjectReplacer.Replace(routingPipeline(pipeline)),
+		natsSubjectReplacer.Replace(event.Resource),
+		natsSubjectReplacer.Replace(event.Event),
+	}, ".")
+}
@@ -0,0 +1,199 @@
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// helper: start an embedded NATS server with JetStream enabled
+func newEmbeddedNATS(t *testing.T) *server.Server {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	return ns
+}
+
+// helper: nats:// target url for an embedded server
+func natsTargetURL(ns *server.Server) string {
+	return "nats://" + strings.TrimPrefix(ns.ClientURL(), "nats://")
+}
+
+// helper: subscribe to subject and return a channel of received messages
+func subscribeNATS(t *testing.T, ns *server.Server, subject string) <-chan *nats.Msg {
+	t.Helper()
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect subscriber: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	ch := make(chan *nats.Msg, 10)
+	if _, err := nc.ChanSubscribe(subject, ch); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("failed to flush subscription: %v", err)
+	}
+	return ch
+}
+
+func TestNATSSubject(t *testing.T) {
+	event := config.WebhookEvent{Resource: "messages", Event: "created"}
+
+	if got := natsSubject("webex", "bot", event); got != "webex.bot.messages.created" {
+		t.Errorf("natsSubject() = %q, want %q", got, "webex.bot.messages.created")
+	}
+	if got := natsSubject("webex", "", event); got != "webex.default.messages.created" {
+		t.Errorf("natsSubject() with empty pipeline = %q, want default token", got)
+	}
+	if got := natsSubject("acme.webex", "my bot.v2", event); got != "acme.webex.my_bot_v2.messages.created" {
+		t.Errorf("natsSubject() = %q, want pipeline dots and spaces replaced", got)
+	}
+}
+
+func TestNATSSender_CorePublish(t *testing.T) {
+	ns := newEmbeddedNATS(t)
+	msgs := subscribeNATS(t, ns, "webex.bot.>")
+
+	s, err := NewSender("bot", config.Target{URL: natsTargetURL(ns)})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{
+		Resource: "messages",
+		Event:    "created",
+		Data:     map[string]interface{}{"id": "msg-1"},
+	}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	select {
+	case msg := <-msgs:
+		if msg.Subject != "webex.bot.messages.created" {
+			t.Errorf("subject = %q, want %q", msg.Subject, "webex.bot.messages.created")
+		}
+		var decoded config.WebhookEvent
+		if err := json.Unmarshal(msg.Data, &decoded); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if decoded.Resource != "messages" {
+			t.Errorf("resource = %q, want %q", decoded.Resource, "messages")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for nats message")
+	}
+}
+
+func TestNATSSender_JetStreamAck(t *testing.T) {
+	ns := newEmbeddedNATS(t)
+
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, _ := jetstream.New(nc)
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:     "WEBEX",
+		Subjects: []string{"webex.>"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+
+	target := config.Target{URL: natsTargetURL(ns), NATS: &config.NATSOptions{JetStream: true}}
+	s, err := NewSender("bot", target)
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{
+		Resource: "rooms",
+		Event:    "updated",
+		Data:     map[string]interface{}{"id": "room-1"},
+	}
+	// Sending the same activity twice should be deduplicated by message id
+	for i := 0; i < 2; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	info, err := stream.Info(context.Background())
+	if err != nil {
+		t.Fatalf("stream info error: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream messages = %d, want 1", info.State.Msgs)
+	}
+}
+
+func TestNATSSender_JetStreamNoStreamFails(t *testing.T) {
+	ns := newEmbeddedNATS(t)
+
+	target := config.Target{URL: natsTargetURL(ns), NATS: &config.NATSOptions{JetStream: true}}
+	s, err := NewSender("bot", target)
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	if err := s.Send(event); err == nil {
+		t.Fatal("Send() should fail when no stream captures the subject")
+	}
+}
+
+func TestNATSSender_ProbeUnreachable(t *testing.T) {
+	s, err := NewSender("bot", config.Target{URL: "nats://127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+
+	if err := s.Probe(); err == nil {
+		t.Error("Probe() should fail for unreachable server")
+	}
+}
+
+func TestBalancer_NATSTargetMarkedUnhealthyWithoutStream(t *testing.T) {
+	ns := newEmbeddedNATS(t)
+	b := newTestBalancer(t, "bot", []config.Target{
+		{URL: natsTargetURL(ns), NATS: &config.NATSOptions{JetStream: true}},
+	})
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	for i := 0; i < maxConsecutiveFailures; i++ {
+		_ = b.Forward(event)
+	}
+
+	if b.HealthyCount() != 0 {
+		t.Error("jetstream target without acks should be marked unhealthy")
+	}
+}
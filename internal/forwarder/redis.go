@@ -0,0 +1,127 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// redisSender appends events to a Redis stream (XADD) or publishes them to
+// a pub/sub channel. go-redis pools and re-dials connections itself, so a
+// PING is enough to probe an unhealthy server.
+type redisSender struct {
+	client   *redis.Client
+	addr     string
+	pipeline string
+	mode     string
+	key      string
+	maxLen   int64
+}
+
+func newRedisSender(pipeline string, target config.Target, _ *url.URL) (Sender, error) {
+	opts, err := redis.ParseURL(target.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis target %q: %w", target.URL, err)
+	}
+	opts.DialTimeout = healthCheckTimeout
+	opts.ReadTimeout = healthCheckTimeout
+	opts.WriteTimeout = healthCheckTimeout
+
+	s := &redisSender{
+		addr:     opts.Addr,
+		pipeline: pipeline,
+		mode:     config.RedisModeStream,
+		key:      config.DefaultRedisKey,
+	}
+
+	if r := target.Redis; r != nil {
+		if r.Mode != "" {
+			s.mode = r.Mode
+		}
+		if r.Key != "" {
+			s.key = r.Key
+		}
+		s.maxLen = r.MaxLen
+		if r.Username != "" {
+			opts.Username = r.Username
+		}
+		if r.PasswordEnv != "" {
+			opts.Password = os.Getenv(r.PasswordEnv)
+		}
+
+		tlsCfg, err := buildTLSConfig(r.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if tlsCfg != nil {
+			opts.TLSConfig = tlsCfg
+		}
+	}
+
+	s.client = redis.NewClient(opts)
+	return s, nil
+}
+
+// Send writes the event as compact JSON to its stream or channel.
+func (s *redisSender) Send(event config.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	key := expandRoutingTemplate(s.key, s.pipeline, event)
+
+	if s.mode == config.RedisModePubSub {
+		receivers, err := s.client.Publish(ctx, key, payload).Result()
+		if err != nil {
+			return fmt.Errorf("redis publish to %s failed: %w", key, err)
+		}
+		display.Println(display.Info(fmt.Sprintf("event forwarded to redis channel %s (%d subscribers)", key, receivers)))
+		return nil
+	}
+
+	id, err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: map[string]interface{}{
+			"resource": event.Resource,
+			"event":    event.Event,
+			"payload":  payload,
+		},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("redis xadd to %s failed: %w", key, err)
+	}
+	display.Println(display.Info(fmt.Sprintf("event forwarded to redis stream %s (id %s)", key, id)))
+	return nil
+}
+
+// Probe sends a PING.
+func (s *redisSender) Probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis ping to %s failed: %w", s.addr, err)
+	}
+	return nil
+}
+
+// Close closes the connection pool.
+func (s *redisSender) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,127 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// helper: new redis sender against a miniredis server, closed in cleanup
+func newTestRedisSender(t *testing.T, mr *miniredis.Miniredis, opts *config.RedisOptions) Sender {
+	t.Helper()
+	s, err := NewSender("bot", config.Target{URL: "redis://" + mr.Addr() + "/0", Redis: opts})
+	if err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestRedisKey(t *testing.T) {
+	event := config.WebhookEvent{Resource: "messages", Event: "created"}
+
+	if got := expandRoutingTemplate(config.DefaultRedisKey, "bot", event); got != "webex:bot:messages:created" {
+		t.Errorf("expandRoutingTemplate() = %q, want %q", got, "webex:bot:messages:created")
+	}
+	if got := expandRoutingTemplate("events:{resource}", "", event); got != "events:messages" {
+		t.Errorf("expandRoutingTemplate() = %q, want %q", got, "events:messages")
+	}
+	if got := expandRoutingTemplate(config.DefaultRedisKey, "", event); got != "webex:default:messages:created" {
+		t.Errorf("expandRoutingTemplate() with empty pipeline = %q, want default token", got)
+	}
+}
+
+func TestRedisSender_StreamXAdd(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newTestRedisSender(t, mr, nil)
+
+	event := config.WebhookEvent{
+		Resource: "messages",
+		Event:    "created",
+		Data:     map[string]interface{}{"id": "msg-1"},
+	}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	entries, err := mr.Stream("webex:bot:messages:created")
+	if err != nil {
+		t.Fatalf("stream lookup error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("stream entries = %d, want 1", len(entries))
+	}
+
+	fields := map[string]string{}
+	for i := 0; i+1 < len(entries[0].Values); i += 2 {
+		fields[entries[0].Values[i]] = entries[0].Values[i+1]
+	}
+	if fields["resource"] != "messages" || fields["event"] != "created" {
+		t.Errorf("fields = %v, want resource/event set", fields)
+	}
+	var decoded config.WebhookEvent
+	if err := json.Unmarshal([]byte(fields["payload"]), &decoded); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if decoded.Resource != "messages" {
+		t.Errorf("payload resource = %q, want %q", decoded.Resource, "messages")
+	}
+}
+
+func TestRedisSender_StreamMaxLen(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newTestRedisSender(t, mr, &config.RedisOptions{Key: "webex:all", MaxLen: 3})
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+	for i := 0; i < 10; i++ {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	entries, err := mr.Stream("webex:all")
+	if err != nil {
+		t.Fatalf("stream lookup error: %v", err)
+	}
+	if len(entries) > 3 {
+		t.Errorf("stream entries = %d, want trimmed to at most 3", len(entries))
+	}
+}
+
+func TestRedisSender_PubSub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newTestRedisSender(t, mr, &config.RedisOptions{Mode: config.RedisModePubSub})
+
+	event := config.WebhookEvent{Resource: "memberships", Event: "deleted", Data: map[string]interface{}{}}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	if mr.Exists("webex:bot:memberships:deleted") {
+		t.Error("pubsub mode should not create a stream key")
+	}
+}
+
+func TestRedisSender_ProbeAndBalancerRecovery(t *testing.T) {
+	mr := miniredis.RunT(t)
+	b := newTestBalancer(t, "bot", []config.Target{{URL: "redis://" + mr.Addr() + "/0"}})
+
+	event := config.WebhookEvent{Resource: "rooms", Event: "updated", Data: map[string]interface{}{}}
+
+	mr.SetError("LOADING server is loading")
+	for i := 0; i < maxConsecutiveFailures; i++ {
+		_ = b.Forward(event)
+	}
+	if b.HealthyCount() != 0 {
+		t.Fatal("redis target should be unhealthy while the server errors")
+	}
+
+	mr.SetError("")
+	b.probeUnhealthy()
+	if b.HealthyCount() != 1 {
+		t.Error("redis target should recover after a successful PING")
+	}
+}
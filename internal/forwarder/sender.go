@@ -0,0 +1,143 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// Sender delivers events to a single forwarding target. Each URL scheme has
+// its own implementation; the Balancer and fanout dispatch only ever talk to
+// this interface, so every target kind gets the same retry and health logic.
+type Sender interface {
+	// Send delivers one event. A non-nil error counts as a failed delivery.
+	Send(event config.WebhookEvent) error
+
+	// Probe checks whether an unhealthy target is reachable again.
+	Probe() error
+
+	// Close releases any connections held by the sender.
+	Close() error
+}
+
+// senderFactory builds a Sender for a parsed target URL.
+type senderFactory func(pipeline string, target config.Target, u *url.URL) (Sender, error)
+
+// senderFactories maps URL schemes to their Sender constructors.
+var senderFactories = map[string]senderFactory{
+	"http":   newHTTPSender,
+	"https":  newHTTPSender,
+	"kafka":  newKafkaSender,
+	"nats":   newNATSSender,
+	"redis":  newRedisSender,
+	"rediss": newRedisSender,
+	"amqp":   newAMQPSender,
+	"amqps":  newAMQPSender,
+	"grpc":   newGRPCSender,
+	"grpcs":  newGRPCSender,
+	"file":   newFileSender,
+	"stdout": newStdoutSender,
+	"exec":   newExecSender,
+}
+
+// NewSender returns the Sender for the target's URL scheme. The pipeline
+// name is passed through for kinds that derive routing from it.
+func NewSender(pipeline string, target config.Target) (Sender, error) {
+	u, err := url.Parse(target.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target url %q: %w", target.URL, err)
+	}
+
+	factory, ok := senderFactories[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported target scheme %q in %q", u.Scheme, target.URL)
+	}
+	return factory(pipeline, target, u)
+}
+
+// NewSenders builds one Sender per target, closing any already created if a
+// later target fails.
+func NewSenders(pipeline string, targets []config.Target) ([]Sender, error) {
+	senders := make([]Sender, 0, len(targets))
+	for _, t := range targets {
+		s, err := NewSender(pipeline, t)
+		if err != nil {
+			for _, prev := range senders {
+				_ = prev.Close()
+			}
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	return senders, nil
+}
+
+// routingPipeline returns the pipeline name used in subjects, stream keys
+// and routing keys. The single-pipeline modes have no name and use "default".
+func routingPipeline(pipeline string) string {
+	if pipeline == "" {
+		return "default"
+	}
+	return pipeline
+}
+
+// expandRoutingTemplate fills {pipeline}, {resource} and {event} in a
+// stream key or routing key template.
+func expandRoutingTemplate(template, pipeline string, event config.WebhookEvent) string {
+	return strings.NewReplacer(
+		"{pipeline}", routingPipeline(pipeline),
+		"{resource}", event.Resource,
+		"{event}", event.Event,
+	).Replace(template)
+}
+
+// eventDataString returns a string field from the event data map, or ""
+// when the data is not a map or the field is missing.
+func eventDataString(event config.WebhookEvent, field string) string {
+	data, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	v, _ := data[field].(string)
+	return v
+}
+
+// httpSender POSTs events to an HTTP(S) URL.
+type httpSender struct {
+	url         string
+	probeClient *http.Client
+}
+
+func newHTTPSender(_ string, target config.Target, _ *url.URL) (Sender, error) {
+	return &httpSender{
+		url:         target.URL,
+		probeClient: &http.Client{Timeout: healthCheckTimeout},
+	}, nil
+}
+
+// Send delegates to ForwardToURL.
+func (s *httpSender) Send(event config.WebhookEvent) error {
+	return ForwardToURL(s.url, event)
+}
+
+// Probe sends a HEAD request. Any response means the target is reachable.
+func (s *httpSender) Probe() error {
+	resp, err := s.probeClient.Head(s.url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Close is a no-op; HTTP targets hold no dedicated connections.
+func (s *httpSender) Close() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package forwarder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+func TestNewSender_HTTPSchemes(t *testing.T) {
+	for _, u := range []string{"http://localhost:8080", "https://example.com/hook"} {
+		s, err := NewSender("test", config.Target{URL: u})
+		if err != nil {
+			t.Fatalf("NewSender(%q) error: %v", u, err)
+		}
+		if _, ok := s.(*httpSender); !ok {
+			t.Errorf("NewSender(%q) = %T, want *httpSender", u, s)
+		}
+	}
+}
+
+func TestNewSender_UnsupportedScheme(t *testing.T) {
+	if _, err := NewSender("test", config.Target{URL: "gopher://localhost"}); err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+}
+
+func TestTargetSchemesHaveSenders(t *testing.T) {
+	for _, scheme := range config.TargetSchemes {
+		if _, ok := senderFactories[scheme]; !ok {
+			t.Errorf("config.TargetSchemes lists %q but no sender handles it", scheme)
+		}
+	}
+	if len(senderFactories) != len(config.TargetSchemes) {
+		t.Errorf("senderFactories has %d schemes, config.TargetSchemes has %d", len(senderFactories), len(config.TargetSchemes))
+	}
+}
+
+func TestNewSenders_FailsOnAnyBadTarget(t *testing.T) {
+	targets := targetsFromURLs("http://localhost:8080", "gopher://localhost")
+	if _, err := NewSenders("test", targets); err == nil {
+		t.Fatal("expected error when one target is unsupported")
+	}
+}
+
+func TestHTTPSender_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	s, _ := NewSender("test", config.Target{URL: server.URL})
+	if err := s.Probe(); err != nil {
+		t.Errorf("Probe() should succeed on any response, got %v", err)
+	}
+
+	unreachable, _ := NewSender("test", config.Target{URL: "http://127.0.0.1:1"})
+	if err := unreachable.Probe(); err == nil {
+		t.Error("Probe() should fail for unreachable target")
+	}
+}
+
+func TestStdoutSender_CompactJSONLine(t *testing.T) {
+	var buf bytes.Buffer
+	s := &stdoutSender{w: &buf}
+
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}}
+	if err := s.Send(event); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Errorf("output = %q, want a single line", out)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("output should not contain ANSI escapes")
+	}
+	var decoded config.WebhookEvent
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Errorf("output is not JSON: %v", err)
+	}
+}
+
+func TestStdoutSender_MovesLogsToStderr(t *testing.T) {
+	var logs bytes.Buffer
+	display.SetOutput(&logs)
+	t.Cleanup(func() { display.SetOutput(os.Stdout) })
+
+	targets := []config.Target{{URL: "http://localhost:8080"}, {URL: "stdout://"}}
+	if !WritesStdout(targets) || WritesStdout(targets[:1]) {
+		t.Error("WritesStdout() should only report the stdout:// target")
+	}
+	if _, err := NewSender("test", targets[1]); err != nil {
+		t.Fatalf("NewSender() error: %v", err)
+	}
+	display.Println("status")
+	if logs.Len() != 0 {
+		t.Errorf("log line = %q, want it moved off the previous writer", logs.String())
+	}
+}
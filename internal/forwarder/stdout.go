@@ -0,0 +1,71 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package forwarder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// stdoutMu serialises writes from all stdout:// targets so lines from
+// concurrent pipelines never interleave.
+var stdoutMu sync.Mutex
+
+// stdoutSender prints one compact JSON line per event with no colouring,
+// so output can be piped straight into jq or a log shipper.
+type stdoutSender struct {
+	w io.Writer
+}
+
+// newStdoutSender claims stdout for events, moving hookbuster's own log
+// lines to stderr.
+func newStdoutSender(_ string, _ config.Target, _ *url.URL) (Sender, error) {
+	display.SetOutput(os.Stderr)
+	return &stdoutSender{w: os.Stdout}, nil
+}
+
+// WritesStdout reports whether any of targets is a stdout:// target.
+// Callers use it to move log lines to stderr before any are printed.
+func WritesStdout(targets []config.Target) bool {
+	for _, t := range targets {
+		if u, err := url.Parse(t.URL); err == nil && u.Scheme == "stdout" {
+			return true
+		}
+	}
+	return false
+}
+
+// Send writes the event as a single JSON line.
+func (s *stdoutSender) Send(event config.WebhookEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	if _, err := s.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+	return nil
+}
+
+// Probe always succeeds; stdout is always there.
+func (s *stdoutSender) Probe() error {
+	return nil
+}
+
+// Close is a no-op; stdout is never closed.
+func (s *stdoutSender) Close() error {
+	return nil
+}
@@ -0,0 +1,151 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package hydra converts the UUIDs Mercury uses to the base64 "Hydra" IDs
+// of the Webex REST API, e.g. Y2lzY29zcGFyazovL3VzL01FU1NBR0Uv... for
+// ciscospark://us/MESSAGE/<uuid>.
+package hydra
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Resource types used in Hydra IDs.
+const (
+	Message          = "MESSAGE"
+	People           = "PEOPLE"
+	Room             = "ROOM"
+	Team             = "TEAM"
+	Organization     = "ORGANIZATION"
+	AttachmentAction = "ATTACHMENT_ACTION"
+)
+
+// DefaultCluster is the cluster of Hydra IDs for resources in the US
+// data centres, of people and organizations, and of any resource whose
+// cluster is not known.
+const DefaultCluster = "us"
+
+// usClusters are the internal cluster IDs whose resources use DefaultCluster
+// in their Hydra IDs, for compatibility with IDs issued before there were
+// other clusters.
+var usClusters = map[string]bool{
+	"urn:TEAM:us-east-2_a":     true,
+	"urn:TEAM:us-east-1_int13": true,
+}
+
+// ClusterFromID returns the Hydra cluster of an internal cluster ID from
+// the service catalog, such as "urn:TEAM:eu-central-1_k:conversation".
+func ClusterFromID(clusterID string) string {
+	parts := strings.Split(clusterID, ":")
+	if len(parts) < 3 || parts[2] == "" {
+		return DefaultCluster
+	}
+	cluster := strings.Join(parts[:3], ":")
+	if usClusters[cluster] {
+		return DefaultCluster
+	}
+	return cluster
+}
+
+// Resolver maps service hosts to the Hydra cluster of the resources they
+// serve, using the host catalog a device receives when it registers.
+type Resolver struct {
+	hosts map[string]string // lower-cased host -> Hydra cluster
+}
+
+// NewResolver builds a Resolver from a device's serviceHostMap, whose
+// hostCatalog lists each service host with its cluster ID. A missing or
+// malformed catalog gives a Resolver that only knows DefaultCluster.
+func NewResolver(serviceHostMap interface{}) *Resolver {
+	r := &Resolver{hosts: make(map[string]string)}
+	var catalog struct {
+		HostCatalog map[string][]struct {
+			Host string `json:"host"`
+			ID   string `json:"id"`
+		} `json:"hostCatalog"`
+	}
+	raw, err := json.Marshal(serviceHostMap)
+	if err != nil || json.Unmarshal(raw, &catalog) != nil {
+		return r
+	}
+	for key, entries := range catalog.HostCatalog {
+		for _, e := range entries {
+			host := e.Host
+			if host == "" {
+				host = key
+			}
+			if e.ID != "" {
+				r.hosts[strings.ToLower(host)] = ClusterFromID(e.ID)
+			}
+		}
+	}
+	return r
+}
+
+// ClusterOf returns the Hydra cluster of the resource at a service URL,
+// such as an activity's target URL. Hosts missing from the catalog, and
+// a nil Resolver, give DefaultCluster.
+func (r *Resolver) ClusterOf(serviceURL string) string {
+	if r == nil {
+		return DefaultCluster
+	}
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return DefaultCluster
+	}
+	if cluster, ok := r.hosts[strings.ToLower(u.Hostname())]; ok {
+		return cluster
+	}
+	return DefaultCluster
+}
+
+// ID returns the Hydra ID of a resource UUID in the default cluster. IDs
+// that are already Hydra IDs are returned unchanged.
+func ID(resourceType, id string) string {
+	return IDIn(DefaultCluster, resourceType, id)
+}
+
+// IDIn returns the Hydra ID of a resource UUID in cluster. IDs that are
+// already Hydra IDs are returned unchanged.
+func IDIn(cluster, resourceType, id string) string {
+	if IsID(id) {
+		return id
+	}
+	return base64.RawStdEncoding.EncodeToString([]byte("ciscospark://" + cluster + "/" + resourceType + "/" + id))
+}
+
+// IsID reports whether id is a Hydra ID.
+func IsID(id string) bool {
+	_, _, ok := Parse(id)
+	return ok
+}
+
+// Parse returns the resource type and UUID of a Hydra ID. ok is false when
+// id is not a Hydra ID.
+func Parse(id string) (resourceType, uuid string, ok bool) {
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(id, "="))
+	if err != nil {
+		return "", "", false
+	}
+	rest, found := strings.CutPrefix(string(decoded), "ciscospark://")
+	if !found {
+		return "", "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// UUID returns the UUID of a Hydra ID, or id itself when it is not one.
+func UUID(id string) string {
+	if _, uuid, ok := Parse(id); ok {
+		return uuid
+	}
+	return id
+}
@@ -0,0 +1,101 @@
+package hydra
+
+import "testing"
+
+// ── Conversion tests ────────────────────────────────────────────────────
+
+func TestID(t *testing.T) {
+	const uuid = "92db3be0-43bd-11e6-8ae9-dd5b3dfc565d"
+	want := "Y2lzY29zcGFyazovL3VzL01FU1NBR0UvOTJkYjNiZTAtNDNiZC0xMWU2LThhZTktZGQ1YjNkZmM1NjVk"
+	if got := ID(Message, uuid); got != want {
+		t.Errorf("ID(%q) = %q, want %q", uuid, got, want)
+	}
+	if got := ID(Message, want); got != want {
+		t.Errorf("ID(%q) = %q, want it unchanged", want, got)
+	}
+	if IsID(uuid) {
+		t.Errorf("IsID(%q) = true", uuid)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		id       string
+		wantType string
+		wantUUID string
+		wantOK   bool
+	}{
+		{"Y2lzY29zcGFyazovL3VzL1BFT1BMRS9mNWIzNjE4Ny1jOGRkLTQ3MjctOGIyZi1mOWM0NDdmMjkwNDY", People, "f5b36187-c8dd-4727-8b2f-f9c447f29046", true},
+		{"Y2lzY29zcGFyazovL3VzL1BFT1BMRS9mNWIzNjE4Ny1jOGRkLTQ3MjctOGIyZi1mOWM0NDdmMjkwNDY=", People, "f5b36187-c8dd-4727-8b2f-f9c447f29046", true},
+		{"f5b36187-c8dd-4727-8b2f-f9c447f29046", "", "", false},
+		{"aGVsbG8", "", "", false},
+	}
+	for _, tt := range tests {
+		typ, uuid, ok := Parse(tt.id)
+		if typ != tt.wantType || uuid != tt.wantUUID || ok != tt.wantOK {
+			t.Errorf("Parse(%q) = %q, %q, %v", tt.id, typ, uuid, ok)
+		}
+	}
+	if got := UUID("f5b36187"); got != "f5b36187" {
+		t.Errorf("UUID() = %q, want the id unchanged", got)
+	}
+}
+
+// testHostMap is a device serviceHostMap for an account in the EU cluster.
+var testHostMap = map[string]interface{}{
+	"hostCatalog": map[string]interface{}{
+		"conv-k.wbx2.com": []interface{}{
+			map[string]interface{}{"host": "conv-k.wbx2.com", "id": "urn:TEAM:eu-central-1_k:conversation"},
+		},
+		"conv-r.wbx2.com": []interface{}{
+			map[string]interface{}{"host": "conv-r.wbx2.com", "id": "urn:TEAM:eu-west-1_r:conversation"},
+		},
+		"conv-a.wbx2.com": []interface{}{
+			map[string]interface{}{"host": "conv-a.wbx2.com", "id": "urn:TEAM:us-east-2_a:conversation"},
+		},
+	},
+}
+
+func TestIDIn_Cluster(t *testing.T) {
+	const uuid = "92db3be0-43bd-11e6-8ae9-dd5b3dfc565d"
+	r := NewResolver(testHostMap)
+	cluster := r.ClusterOf("https://conv-k.wbx2.com/conversation/api/v1/conversations/" + uuid)
+	if cluster != "urn:TEAM:eu-central-1_k" {
+		t.Fatalf("ClusterOf() = %q", cluster)
+	}
+	id := IDIn(cluster, Room, uuid)
+	typ, got, ok := Parse(id)
+	if !ok || typ != Room || got != uuid {
+		t.Errorf("Parse(IDIn()) = %q, %q, %v", typ, got, ok)
+	}
+}
+
+func TestResolver_ClusterOf(t *testing.T) {
+	r := NewResolver(testHostMap)
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://CONV-R.wbx2.com/conversation/api/v1/conversations/x", "urn:TEAM:eu-west-1_r"},
+		{"https://conv-a.wbx2.com/conversation/api/v1/conversations/x", DefaultCluster},
+		{"https://conv-unknown.example.com/conversation/api/v1/conversations/x", DefaultCluster},
+		{"", DefaultCluster},
+		{"://bad", DefaultCluster},
+	}
+	for _, tt := range tests {
+		if got := r.ClusterOf(tt.url); got != tt.want {
+			t.Errorf("ClusterOf(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+
+	var none *Resolver
+	if got := none.ClusterOf("https://conv-k.wbx2.com/x"); got != DefaultCluster {
+		t.Errorf("nil ClusterOf() = %q, want %q", got, DefaultCluster)
+	}
+	if got := NewResolver("not a catalog").ClusterOf("https://conv-k.wbx2.com/x"); got != DefaultCluster {
+		t.Errorf("ClusterOf() without a catalog = %q, want %q", got, DefaultCluster)
+	}
+	if got := ClusterFromID("urn:TEAM:us-east-1_int13:conversation"); got != DefaultCluster {
+		t.Errorf("ClusterFromID(integration) = %q, want %q", got, DefaultCluster)
+	}
+}
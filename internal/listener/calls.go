@@ -0,0 +1,143 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package listener
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	webex "github.com/WebexCommunity/webex-go-sdk/v2"
+	"github.com/WebexCommunity/webex-go-sdk/v2/calling"
+	"github.com/WebexCommunity/webex-go-sdk/v2/mercury"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// connectCalls registers a Webex Calling line through the SDK's calling
+// client, which Mobius needs before it delivers the account's call events,
+// and connects the line's own Mercury client. The conversation's Mercury
+// client is left alone, as the calling client takes over its wildcard
+// handlers. Hookbuster only reports calls; it never answers or ends them.
+// It does nothing if the line is already connected.
+func (l *Listener) connectCalls() error {
+	l.mu.Lock()
+	connected := l.callingClient != nil
+	l.mu.Unlock()
+	if connected {
+		return nil
+	}
+
+	cc := newCallingClient(l.client)
+	if err := cc.DiscoverMobiusServers(); err != nil {
+		return fmt.Errorf("failed to discover Mobius servers: %w", err)
+	}
+	line, err := cc.CreateLine()
+	if err != nil {
+		return err
+	}
+	cc.Emitter.On(string(calling.LineEventIncomingCall), func(data interface{}) {
+		if call, ok := data.(*calling.Call); ok {
+			l.watchCall(call)
+		}
+	})
+
+	display.Println(display.Info("Connecting calling line..."))
+	if err := cc.ConnectMercury(mercury.New(l.client.Core(), nil)); err != nil {
+		if derr := line.Deregister(); derr != nil {
+			display.Println(display.Error(fmt.Sprintf("%serror deregistering calling line: %s", l.logPrefix(), derr.Error())))
+		}
+		return err
+	}
+
+	l.mu.Lock()
+	l.callingClient = cc
+	l.callLine = line
+	l.mu.Unlock()
+
+	display.Println(display.Info("Calling line registered!"))
+	return nil
+}
+
+// newCallingClient returns the client's calling client. When EnvWDMURL is
+// set, a calling client is built by hand so its line's device registers
+// with the overridden service.
+func newCallingClient(client *webex.WebexClient) *calling.CallingClient {
+	wdmURL := os.Getenv(EnvWDMURL)
+	if wdmURL == "" {
+		return client.Calling().CallingClient(nil)
+	}
+	cfg := calling.DefaultConfig()
+	cfg.WDMURL = wdmURL
+	return calling.NewCallingClient(client.Core(), cfg, nil)
+}
+
+// watchCall publishes calls:incoming for a new incoming call, then
+// calls:answered and calls:disconnected when Mobius reports the call
+// connected and disconnected. Each is published at most once per call.
+func (l *Listener) watchCall(call *calling.Call) {
+	var (
+		mu       sync.Mutex
+		callerID *calling.CallerIDInfo
+	)
+	emit := func(name string) {
+		if !l.subscribed("calls", name) {
+			return
+		}
+		mu.Lock()
+		id := callerID
+		mu.Unlock()
+		l.publish("calls", name, buildCallEventData(call, id), hydra.DefaultCluster)
+	}
+
+	var answered, disconnected sync.Once
+	call.Emitter.On(string(calling.CallEventCallerID), func(data interface{}) {
+		if id, ok := data.(*calling.CallerIDInfo); ok {
+			mu.Lock()
+			callerID = id
+			mu.Unlock()
+		}
+	})
+	call.Emitter.On(string(calling.CallEventConnect), func(interface{}) {
+		answered.Do(func() { emit("answered") })
+	})
+	call.Emitter.On(string(calling.CallEventDisconnect), func(interface{}) {
+		disconnected.Do(func() { emit("disconnected") })
+	})
+	emit("incoming")
+}
+
+// buildCallEventData constructs the data payload for a call event. callerId
+// is set once Mobius has sent the caller's identity for the call.
+func buildCallEventData(call *calling.Call, callerID *calling.CallerIDInfo) map[string]interface{} {
+	data := map[string]interface{}{
+		"callId":        call.GetCallID(),
+		"correlationId": call.GetCorrelationID(),
+		"direction":     string(call.GetDirection()),
+		"callState":     string(call.GetState()),
+		"published":     time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	if callerID != nil && callerID.From != "" {
+		data["callerId"] = callerID.From
+	}
+	return data
+}
+
+// disconnectCalls disconnects the calling line's Mercury client and
+// deregisters the line. Calls in progress are left to the other devices.
+// The caller holds l.mu.
+func (l *Listener) disconnectCalls() {
+	if l.callingClient == nil {
+		return
+	}
+	l.callingClient.DisconnectMercury()
+	if err := l.callLine.Deregister(); err != nil {
+		display.Println(display.Error(fmt.Sprintf("%serror deregistering calling line: %s", l.logPrefix(), err.Error())))
+	}
+	l.callingClient = nil
+	l.callLine = nil
+}
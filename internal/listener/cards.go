@@ -0,0 +1,100 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package listener
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/WebexCommunity/webex-go-sdk/v2/conversation"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// adaptiveCardContentType is the attachment content type of adaptive cards.
+const adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+
+// addCardActionFields gives a cardAction event the fields of the REST
+// attachment/actions resource: type, messageId, inputs, personId and
+// created. The submitted card is looked up on the card's message, in the
+// room's cluster, and added as card. Failures are logged and leave the
+// field out, so the event is still forwarded with its raw object.
+func (l *Listener) addCardActionFields(data map[string]interface{}, activity *conversation.Activity, cluster string) {
+	if actionType, ok := activity.Object["objectType"].(string); ok {
+		data["type"] = actionType
+	}
+	if activity.Actor != nil {
+		data["personId"] = activity.Actor.ID
+	}
+	if activity.Published != "" {
+		data["created"] = activity.Published
+	}
+
+	inputs, err := l.cardInputs(activity)
+	if err != nil {
+		display.Println(display.Error(fmt.Sprintf("[%s] card action %s: %s", l.name, activity.ID, err.Error())))
+	} else if inputs != nil {
+		data["inputs"] = inputs
+	}
+
+	messageID, _ := rawParent(activity)["id"].(string)
+	if messageID == "" {
+		return
+	}
+	data["messageId"] = messageID
+
+	l.mu.Lock()
+	getMessage := l.getMessage
+	l.mu.Unlock()
+	if getMessage == nil {
+		return
+	}
+	msg, err := getMessage(hydra.IDIn(cluster, hydra.Message, messageID))
+	if err != nil {
+		display.Println(display.Error(fmt.Sprintf("[%s] card action %s: failed to look up card: %s", l.name, activity.ID, err.Error())))
+		return
+	}
+	for _, a := range msg.Attachments {
+		if a.ContentType == adaptiveCardContentType {
+			data["card"] = a.Content
+			break
+		}
+	}
+}
+
+// cardInputs returns the submitted card inputs. Mercury sends them as
+// encrypted JSON, decrypted with the activity's key; plain objects and
+// plain JSON (as sent by the mock server) are used as they are.
+func (l *Listener) cardInputs(activity *conversation.Activity) (map[string]interface{}, error) {
+	switch raw := activity.Object["inputs"].(type) {
+	case map[string]interface{}:
+		return raw, nil
+	case string:
+		text := raw
+		if !json.Valid([]byte(text)) {
+			keyURL := activity.EncryptionKeyURL
+			if keyURL == "" {
+				keyURL, _ = activity.Object["encryptionKeyUrl"].(string)
+			}
+			l.mu.Lock()
+			decrypt := l.decryptText
+			l.mu.Unlock()
+			if decrypt == nil || keyURL == "" {
+				return nil, fmt.Errorf("inputs are encrypted and no key is available")
+			}
+			var err error
+			if text, err = decrypt(keyURL, raw); err != nil {
+				return nil, fmt.Errorf("failed to decrypt inputs: %w", err)
+			}
+		}
+		var inputs map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &inputs); err != nil {
+			return nil, fmt.Errorf("invalid inputs: %w", err)
+		}
+		return inputs, nil
+	}
+	return nil, nil
+}
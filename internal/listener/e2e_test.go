@@ -0,0 +1,167 @@
+//go:build !race
+
+package listener
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/mockmercury"
+)
+
+// End-to-end tests against mockmercury.
+//
+// The SDK's Mercury client swaps its internal channels on reconnect and
+// disconnect without holding its lock, which the race detector reports. These
+// tests exercise exactly those paths, so they are excluded from -race builds
+// and run as a separate CI step.
+
+const mockToken = "mock-token"
+
+// helper: start a mock Webex and point the listener's endpoints at it
+func startMock(t *testing.T) *mockmercury.Server {
+	t.Helper()
+	m := mockmercury.New(mockmercury.Options{Token: mockToken})
+	if err := m.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	t.Setenv(EnvAPIURL, m.URL()+mockmercury.APIPath)
+	t.Setenv(EnvWDMURL, m.URL()+mockmercury.DevicesPath)
+	return m
+}
+
+// helper: start an HTTP receiver that passes forwarded events to a channel
+func startReceiver(t *testing.T) (string, <-chan config.WebhookEvent) {
+	t.Helper()
+	events := make(chan config.WebhookEvent, 16)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event config.WebhookEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			events <- event
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL, events
+}
+
+// helper: start a fanout listener subscribed to resource/event
+func startE2EListener(t *testing.T, m *mockmercury.Server, target, resource, event string) *Listener {
+	t.Helper()
+	l, err := NewPipelineListener("e2e", mockToken, config.ModeFanout, []config.Target{{URL: target}})
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	if err := l.Start(config.Resources[resource], event); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	t.Cleanup(func() { l.Stop() })
+	if err := m.WaitForConnections(1, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func receive(t *testing.T, events <-chan config.WebhookEvent) config.WebhookEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a forwarded event")
+	}
+	return config.WebhookEvent{}
+}
+
+func TestE2E_VerifyAccessToken(t *testing.T) {
+	startMock(t)
+
+	person, err := VerifyAccessToken(mockToken)
+	if err != nil {
+		t.Fatalf("VerifyAccessToken() error: %v", err)
+	}
+	if person.DisplayName != mockmercury.DefaultMe.DisplayName {
+		t.Errorf("DisplayName = %q, want %q", person.DisplayName, mockmercury.DefaultMe.DisplayName)
+	}
+
+	if _, err := VerifyAccessToken("wrong-token"); err == nil {
+		t.Error("VerifyAccessToken() should fail for a token the mock rejects")
+	}
+}
+
+func TestE2E_FiltersAndForwards(t *testing.T) {
+	m := startMock(t)
+	target, events := startReceiver(t)
+	startE2EListener(t, m, target, "messages", "created")
+
+	for _, a := range []mockmercury.Activity{
+		mockmercury.Delete("room-1", "msg-0"),
+		mockmercury.Add("room-1", "person-1"),
+		mockmercury.Post("room-1", "hello"),
+	} {
+		if err := m.Inject(a); err != nil {
+			t.Fatalf("Inject(%s) error: %v", a.Verb, err)
+		}
+	}
+
+	event := receive(t, events)
+	if event.Resource != "messages" || event.Event != "created" {
+		t.Fatalf("forwarded %s:%s, want messages:created", event.Resource, event.Event)
+	}
+	data, _ := event.Data.(map[string]interface{})
+	if data["content"] != "hello" || data["roomId"] != "room-1" {
+		t.Errorf("data = %v, want content hello in room-1", event.Data)
+	}
+
+	select {
+	case extra := <-events:
+		t.Errorf("unexpected %s:%s forwarded", extra.Resource, extra.Event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestE2E_CardAction(t *testing.T) {
+	m := startMock(t)
+	target, events := startReceiver(t)
+	startE2EListener(t, m, target, "attachmentActions", "all")
+
+	inputs := map[string]interface{}{"choice": "yes"}
+	if err := m.Inject(mockmercury.CardAction("room-1", "card-msg-1", inputs)); err != nil {
+		t.Fatalf("Inject() error: %v", err)
+	}
+
+	event := receive(t, events)
+	if event.Resource != "attachmentActions" || event.Event != "created" {
+		t.Fatalf("forwarded %s:%s, want attachmentActions:created", event.Resource, event.Event)
+	}
+	data, _ := event.Data.(map[string]interface{})
+	if data["parentId"] != "card-msg-1" {
+		t.Errorf("parentId = %v, want card-msg-1", data["parentId"])
+	}
+}
+
+func TestE2E_ReconnectsAfterDrop(t *testing.T) {
+	m := startMock(t)
+	target, events := startReceiver(t)
+	startE2EListener(t, m, target, "memberships", "all")
+
+	m.DropConnections()
+	if err := m.WaitForConnections(1, 10*time.Second); err != nil {
+		t.Fatalf("listener did not reconnect: %v", err)
+	}
+
+	if err := m.Inject(mockmercury.Leave("room-1", "person-1")); err != nil {
+		t.Fatalf("Inject() error: %v", err)
+	}
+	event := receive(t, events)
+	if event.Resource != "memberships" || event.Event != "deleted" {
+		t.Errorf("forwarded %s:%s, want memberships:deleted", event.Resource, event.Event)
+	}
+	if m.Registrations() != 1 {
+		t.Errorf("registrations = %d, want 1 (reconnect should reuse the device)", m.Registrations())
+	}
+}
@@ -0,0 +1,80 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package listener
+
+import (
+	"fmt"
+
+	"github.com/WebexCommunity/webex-go-sdk/v2/conversation"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// invalidateMetadata drops cached details that an activity shows have
+// changed: the room on any room change other than its creation, and on any
+// membership change, since the title of an untitled room is made from its
+// members; and the member on a membership change, so a renamed person is
+// picked up when they join, leave or change role. It runs for every
+// activity, subscribed or not.
+func (l *Listener) invalidateMetadata(activity *conversation.Activity, resource, event string) {
+	l.mu.Lock()
+	cache := l.metadata
+	l.mu.Unlock()
+	if cache == nil || activity.Target == nil || activity.Target.ID == "" {
+		return
+	}
+	if (resource == "rooms" && event != "created") || resource == "memberships" {
+		cache.InvalidateRoom(activity.Target.ID)
+	}
+	if resource == "memberships" {
+		if personID, ok := activity.Object["id"].(string); ok && personID != "" {
+			cache.InvalidatePerson(personID)
+		}
+	}
+}
+
+// enrich adds room details (roomTitle, roomType, isDirect, teamId) for the
+// event's roomId, and the actor's displayName, email and orgId when the
+// event does not carry them, as Mercury status events do not. Lookup
+// failures are logged and leave the fields out.
+func (l *Listener) enrich(data map[string]interface{}, cluster string) {
+	l.mu.Lock()
+	cache, src := l.metadata, l.metadataSource
+	l.mu.Unlock()
+	if cache == nil {
+		return
+	}
+
+	if roomID, ok := data["roomId"].(string); ok && roomID != "" {
+		room, err := cache.Room(src, cluster, roomID)
+		if err != nil {
+			display.Println(display.Error(fmt.Sprintf("[%s] room %s lookup failed: %s", l.name, roomID, err.Error())))
+		} else {
+			data["roomTitle"] = room.Title
+			data["roomType"] = room.Type
+			data["isDirect"] = room.IsDirect()
+			if room.TeamID != "" {
+				data["teamId"] = room.TeamID
+			}
+		}
+	}
+
+	actorID, _ := data["actorId"].(string)
+	if name, _ := data["actorDisplayName"].(string); actorID == "" || name != "" {
+		return
+	}
+	person, err := cache.Person(src, actorID)
+	if err != nil {
+		display.Println(display.Error(fmt.Sprintf("[%s] person %s lookup failed: %s", l.name, actorID, err.Error())))
+		return
+	}
+	data["actorDisplayName"] = person.DisplayName
+	if len(person.Emails) > 0 {
+		data["actorEmail"] = person.Emails[0]
+	}
+	if person.OrgID != "" {
+		data["actorOrgId"] = person.OrgID
+	}
+}
@@ -0,0 +1,81 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package listener
+
+import (
+	"strings"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// idFieldTypes maps the identifier fields of forwarded data to their Hydra
+// resource types.
+var idFieldTypes = map[string]string{
+	"roomId":     hydra.Room,
+	"actorId":    hydra.People,
+	"personId":   hydra.People,
+	"actorOrgId": hydra.Organization,
+	"parentId":   hydra.Message,
+	"messageId":  hydra.Message,
+	"teamId":     hydra.Team,
+}
+
+// clusterScoped holds the Hydra resource types whose IDs carry the room's
+// cluster. People and organizations are global and always use
+// hydra.DefaultCluster.
+var clusterScoped = map[string]bool{
+	hydra.Room:             true,
+	hydra.Message:          true,
+	hydra.Team:             true,
+	hydra.AttachmentAction: true,
+}
+
+// eventIDTypes gives the Hydra resource type of data.id for the events
+// where the activity ID is also the REST resource's ID. Elsewhere, e.g. on
+// message edits and deletes, data.id is the ID of the activity itself and
+// has no REST form.
+var eventIDTypes = map[string]string{
+	"messages:created":          hydra.Message,
+	"attachmentActions:created": hydra.AttachmentAction,
+}
+
+// convertIDs rewrites the identifiers in data to form, using cluster for
+// the Hydra IDs of room-scoped resources. In both form, each converted field keeps its UUID in a
+// "<name>Uuid" field ("uuid" for id). The raw object is left as it is.
+func convertIDs(data map[string]interface{}, resource, event, form, cluster string) {
+	if form == "" || form == config.IDsUUID {
+		return
+	}
+	convert := func(field, resourceType string) {
+		id, ok := data[field].(string)
+		if !ok || id == "" {
+			return
+		}
+		idCluster := hydra.DefaultCluster
+		if clusterScoped[resourceType] {
+			idCluster = cluster
+		}
+		data[field] = hydra.IDIn(idCluster, resourceType, id)
+		if form == config.IDsBoth {
+			data[uuidField(field)] = hydra.UUID(id)
+		}
+	}
+	for field, resourceType := range idFieldTypes {
+		convert(field, resourceType)
+	}
+	if resourceType, ok := eventIDTypes[resource+":"+event]; ok {
+		convert("id", resourceType)
+	}
+}
+
+// uuidField returns the field that keeps the UUID of an ID field in both
+// form: "roomId" becomes "roomUuid" and "id" becomes "uuid".
+func uuidField(field string) string {
+	if field == "id" {
+		return "uuid"
+	}
+	return strings.TrimSuffix(field, "Id") + "Uuid"
+}
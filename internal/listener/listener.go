@@ -6,26 +6,58 @@ package listener
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	webex "github.com/WebexCommunity/webex-go-sdk/v2"
+	"github.com/WebexCommunity/webex-go-sdk/v2/calling"
 	"github.com/WebexCommunity/webex-go-sdk/v2/conversation"
+	"github.com/WebexCommunity/webex-go-sdk/v2/device"
+	"github.com/WebexCommunity/webex-go-sdk/v2/mercury"
+	"github.com/WebexCommunity/webex-go-sdk/v2/messages"
 	"github.com/WebexCommunity/webex-go-sdk/v2/people"
+	"github.com/WebexCommunity/webex-go-sdk/v2/webexsdk"
 
+	"github.com/tejzpr/webex-go-hookbuster/internal/attachments"
 	"github.com/tejzpr/webex-go-hookbuster/internal/config"
 	"github.com/tejzpr/webex-go-hookbuster/internal/display"
 	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+	"github.com/tejzpr/webex-go-hookbuster/internal/metadata"
 )
 
 const errCreateClient = "failed to create Webex client: %w"
 
+// Env vars that redirect the Webex REST API and device registration, e.g.
+// to a `hookbuster mock` server. Unset means the production services.
+const (
+	EnvAPIURL = "HOOKBUSTER_WEBEX_API_URL"
+	EnvWDMURL = "HOOKBUSTER_WEBEX_WDM_URL"
+)
+
+// newWebexClient creates an SDK client, honouring EnvAPIURL.
+func newWebexClient(accessToken string) (*webex.WebexClient, error) {
+	var cfg *webexsdk.Config
+	if base := os.Getenv(EnvAPIURL); base != "" {
+		cfg = webexsdk.DefaultConfig()
+		cfg.BaseURL = base
+	}
+	client, err := webex.NewClient(accessToken, cfg)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateClient, err)
+	}
+	return client, nil
+}
+
 // VerifyAccessToken validates the given access token by calling the
 // Webex People API for the authenticated user ("me").
 func VerifyAccessToken(accessToken string) (*people.Person, error) {
-	client, err := webex.NewClient(accessToken, nil)
+	client, err := newWebexClient(accessToken)
 	if err != nil {
-		return nil, fmt.Errorf(errCreateClient, err)
+		return nil, err
 	}
 
 	person, err := client.People().Get("me")
@@ -36,6 +68,13 @@ func VerifyAccessToken(accessToken string) (*people.Person, error) {
 	return person, nil
 }
 
+// Observer receives a copy of every event a Listener forwards, in addition
+// to its targets. Observe is called on the Mercury read loop and must not
+// block.
+type Observer interface {
+	Observe(pipeline string, event config.WebhookEvent)
+}
+
 // Listener manages the Conversation WebSocket connection and event forwarding.
 type Listener struct {
 	name               string // pipeline name (for logging)
@@ -46,11 +85,21 @@ type Listener struct {
 	running            bool
 
 	// subscriptions tracks which resource/event pairs are active.
-	subscriptions map[string]string // resource name -> event filter ("all" or specific)
+	subscriptions map[string]map[string]bool // resource name -> set of subscribed events
 
-	// targets holds multiple forwarding destinations for multi-pipeline mode.
-	// When empty, the legacy Forward(target, port) path is used.
-	targets []config.Target
+	// meetingStates holds the last Locus state seen per meeting (locus
+	// URL), to derive meetings:started and meetings:ended.
+	meetingStates map[string]string
+
+	// callingClient and callLine are the Webex Calling line registered
+	// for the calls resource, once it is subscribed.
+	callingClient *calling.CallingClient
+	callLine      *calling.Line
+
+	// senders holds one forwarding Sender per target in fanout mode.
+	// When empty (and balancer is nil), the legacy Forward(target, port)
+	// path is used.
+	senders []forwarder.Sender
 
 	// mode is the forwarding strategy: "fanout" (default) or "roundrobin".
 	mode string
@@ -58,28 +107,64 @@ type Listener struct {
 	// balancer is set when mode is "roundrobin". It handles round-robin
 	// forwarding with health checks and retry.
 	balancer *forwarder.Balancer
+
+	// closeOnce closes the senders and balancer on the first Stop, whether
+	// or not the listener ever connected.
+	closeOnce sync.Once
+
+	// observers receive every forwarded event, e.g. the local stream server.
+	observers []Observer
+
+	// attachments, when set, replaces data.files on shared messages with
+	// inlined or stored copies of the files.
+	attachments *attachments.Fetcher
+
+	// metadata, when set, enriches events with room and actor details
+	// looked up through metadataSource.
+	metadata       *metadata.Cache
+	metadataSource metadata.Source
+
+	// idForm is the form of forwarded identifiers (config.IDsUUID,
+	// IDsHydra or IDsBoth); empty means UUIDs.
+	idForm string
+
+	// clusters maps service hosts to Hydra clusters, from the device's
+	// host catalog. It is set on connect; nil resolves every host to
+	// hydra.DefaultCluster.
+	clusters *hydra.Resolver
+
+	// ignoredActors holds the lower-cased UUIDs and email addresses of
+	// actors whose activities are dropped.
+	ignoredActors map[string]bool
+
+	// decryptText and getMessage reach KMS and the messages API for card
+	// actions. They are set on connect, so they stay nil in tests unless a
+	// test sets them.
+	decryptText func(keyURL, ciphertext string) (string, error)
+	getMessage  func(messageID string) (*messages.Message, error)
 }
 
 // NewListener creates a new Listener from the given specs (legacy single-pipeline mode).
 func NewListener(specs *config.Specs) (*Listener, error) {
-	client, err := webex.NewClient(specs.AccessToken, nil)
+	client, err := newWebexClient(specs.AccessToken)
 	if err != nil {
-		return nil, fmt.Errorf(errCreateClient, err)
+		return nil, err
 	}
 
 	return &Listener{
 		specs:         specs,
 		client:        client,
-		subscriptions: make(map[string]string),
+		subscriptions: make(map[string]map[string]bool),
+		meetingStates: make(map[string]string),
 	}, nil
 }
 
 // NewPipelineListener creates a Listener for multi-pipeline mode with named
 // pipeline, forwarding mode, and multiple forwarding targets.
 func NewPipelineListener(name, accessToken, mode string, targets []config.Target) (*Listener, error) {
-	client, err := webex.NewClient(accessToken, nil)
+	client, err := newWebexClient(accessToken)
 	if err != nil {
-		return nil, fmt.Errorf(errCreateClient, err)
+		return nil, err
 	}
 
 	// Default to roundrobin when mode is empty
@@ -90,72 +175,205 @@ func NewPipelineListener(name, accessToken, mode string, targets []config.Target
 	l := &Listener{
 		name:          name,
 		client:        client,
-		targets:       targets,
 		mode:          mode,
-		subscriptions: make(map[string]string),
+		subscriptions: make(map[string]map[string]bool),
+		meetingStates: make(map[string]string),
+	}
+
+	// A pipeline without targets only feeds its observers
+	if len(targets) == 0 {
+		return l, nil
 	}
 
 	if mode == config.ModeRoundRobin {
-		l.balancer = forwarder.NewBalancer(name, targets)
+		l.balancer, err = forwarder.NewBalancer(name, targets)
+	} else {
+		l.senders, err = forwarder.NewSenders(name, targets)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return l, nil
 }
 
-// Start registers the given resource/event for forwarding, and connects
-// the conversation client if not already connected.
-func (l *Listener) Start(resource *config.Resource, event string) error {
+// AddObserver registers o to receive every event this listener forwards.
+// It must be called before Start.
+func (l *Listener) AddObserver(o Observer) {
 	l.mu.Lock()
-	l.subscriptions[resource.Description] = event
-	needsConnect := !l.running
-	l.mu.Unlock()
+	defer l.mu.Unlock()
+	l.observers = append(l.observers, o)
+}
+
+// SetAttachments makes the listener download the files of shared messages
+// as configured by opts. It must be called before Start.
+func (l *Listener) SetAttachments(opts *config.AttachmentOptions) error {
+	f, err := attachments.New(opts, attachments.NewSDKSource(l.client))
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.attachments = f
+	return nil
+}
+
+// SetMetadataCache makes the listener enrich events with room and actor
+// details from c, fetching missing ones with its own token. The cache must
+// not be shared with listeners using other tokens, which may see rooms
+// differently. It must be called before Start.
+func (l *Listener) SetMetadataCache(c *metadata.Cache) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.metadata = c
+	l.metadataSource = metadata.NewSDKSource(l.client)
+}
+
+// SetIDForm sets the form of forwarded identifiers to one of config.IDsUUID,
+// IDsHydra or IDsBoth. It must be called before Start.
+func (l *Listener) SetIDForm(form string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.idForm = form
+}
+
+// IgnoreActors drops activities performed by any of actors, given as
+// person IDs (REST IDs or UUIDs) or email addresses. It must be called
+// before Start.
+func (l *Listener) IgnoreActors(actors ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ignoredActors == nil {
+		l.ignoredActors = make(map[string]bool)
+	}
+	for _, a := range actors {
+		l.ignoredActors[strings.ToLower(hydra.UUID(a))] = true
+	}
+}
+
+// ignored reports whether actor's activities are dropped.
+func (l *Listener) ignored(actor *conversation.Actor) bool {
+	if actor == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ignoredActors[strings.ToLower(hydra.UUID(actor.ID))] ||
+		(actor.EmailAddress != "" && l.ignoredActors[strings.ToLower(actor.EmailAddress)])
+}
 
+// Start registers the given events of a resource for forwarding ("all",
+// or no events, selects every event), and connects the conversation client
+// if not already connected. The calls resource also registers a calling
+// line. Calling Start again for the same resource adds to its events.
+func (l *Listener) Start(resource *config.Resource, events ...string) error {
 	resName := resource.Description
+	expanded, err := expandEvents(resource, events)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	set := l.subscriptions[resName]
+	if set == nil {
+		set = make(map[string]bool)
+		l.subscriptions[resName] = set
+	}
+	for _, ev := range expanded {
+		set[ev] = true
+	}
+	needsConnect := !l.running
+	l.mu.Unlock()
 
-	fmt.Println(display.Info(
+	display.Println(display.Info(
 		fmt.Sprintf("Listening for events from the %s resource", display.Highlight(resName)),
 	))
 
 	// Log individual event handler registrations
-	if event == "all" {
-		for _, ev := range resource.Events {
-			if ev == "all" {
-				continue
-			}
-			fmt.Println(display.Info(
-				fmt.Sprintf("Registered handler to forward %s events",
-					display.Highlight(fmt.Sprintf("%s:%s", resName, ev))),
-			))
-		}
-	} else {
-		fmt.Println(display.Info(
+	for _, ev := range expanded {
+		display.Println(display.Info(
 			fmt.Sprintf("Registered handler to forward %s events",
-				display.Highlight(fmt.Sprintf("%s:%s", resName, event))),
+				display.Highlight(fmt.Sprintf("%s:%s", resName, ev))),
 		))
 	}
 
 	if needsConnect {
-		return l.connect()
+		if err := l.connect(); err != nil {
+			return err
+		}
+	}
+	if resName == "calls" {
+		return l.connectCalls()
 	}
 	return nil
 }
 
+// StartSelection subscribes to every resource and event in sel.
+func (l *Listener) StartSelection(sel config.Selection) error {
+	for _, sub := range sel {
+		res, ok := config.Resources[sub.Resource]
+		if !ok {
+			return fmt.Errorf("unknown resource %q", sub.Resource)
+		}
+		if err := l.Start(res, sub.Events...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandEvents replaces "all" (or an empty list) with the resource's
+// concrete events, keeping the resource's order and dropping duplicates.
+func expandEvents(resource *config.Resource, events []string) ([]string, error) {
+	valid := make(map[string]bool)
+	for _, ev := range resource.Events {
+		valid[ev] = true
+	}
+	wanted := make(map[string]bool)
+	all := len(events) == 0
+	for _, ev := range events {
+		if ev != "all" && !valid[ev] {
+			return nil, fmt.Errorf("resource %s has no event %q", resource.Description, ev)
+		}
+		if ev == "all" {
+			all = true
+		}
+		wanted[ev] = true
+	}
+
+	var expanded []string
+	for _, ev := range resource.Events {
+		if ev != "all" && (all || wanted[ev]) {
+			expanded = append(expanded, ev)
+		}
+	}
+	return expanded, nil
+}
+
 // connect uses the SDK's Conversation() convenience method which handles
 // device registration, Mercury WebSocket wiring, and encryption setup
 // in a single call.
 func (l *Listener) connect() error {
-	// client.Conversation() replaces ~25 lines of manual device/mercury/encryption setup
-	conv, err := l.client.Conversation()
+	conv, merc, dev, err := newConversation(l.client)
 	if err != nil {
 		return fmt.Errorf("failed to initialize conversation client: %w", err)
 	}
 	l.conversationClient = conv
 
-	// Register verb-based handlers
-	l.registerVerbHandlers()
+	l.mu.Lock()
+	l.clusters = hydra.NewResolver(dev.GetDevice().ServiceHostMap)
+	if enc := conv.EncryptionClient(); enc != nil {
+		l.decryptText = enc.DecryptText
+	}
+	l.getMessage = l.client.Messages().Get
+	l.mu.Unlock()
+
+	// Register the activity classifier and the other Mercury event types
+	l.registerActivityHandler()
+	l.registerMercuryHandlers(merc)
 
 	// Connect the WebSocket
-	fmt.Println(display.Info("Connecting to WebSocket..."))
+	display.Println(display.Info("Connecting to WebSocket..."))
 	if err := conv.Connect(); err != nil {
 		return fmt.Errorf("failed to connect to Mercury: %w", err)
 	}
@@ -164,53 +382,258 @@ func (l *Listener) connect() error {
 	l.running = true
 	l.mu.Unlock()
 
-	fmt.Println(display.Info("Connected to WebSocket!"))
-	fmt.Println(display.Info("Webex Initialized"))
+	display.Println(display.Info("Connected to WebSocket!"))
+	display.Println(display.Info("Webex Initialized"))
 
 	return nil
 }
 
-// registerVerbHandlers registers a conversation handler for every verb in
-// the VerbToResourceEvent mapping table.
-func (l *Listener) registerVerbHandlers() {
-	for verb, mapping := range config.VerbToResourceEvent {
+// newConversation returns the client's conversation client and the Mercury
+// client and registered device it is wired to. Normally that is
+// client.Conversation(); when EnvWDMURL is set the same device, Mercury and
+// encryption wiring is done by hand so the device registers with the
+// overridden service.
+func newConversation(client *webex.WebexClient) (*conversation.Client, *mercury.Client, *device.Client, error) {
+	wdmURL := os.Getenv(EnvWDMURL)
+	if wdmURL == "" {
+		conv, err := client.Conversation()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return conv, client.Mercury(), client.Device(), nil
+	}
+
+	devCfg := device.DefaultConfig()
+	devCfg.WDMURL = wdmURL
+	dev := device.New(client.Core(), devCfg)
+	if err := dev.Register(); err != nil {
+		return nil, nil, nil, fmt.Errorf("device registration failed: %w", err)
+	}
+	deviceURL, err := dev.GetDeviceURL()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get device URL: %w", err)
+	}
+
+	merc := mercury.New(client.Core(), nil)
+	merc.SetDeviceProvider(dev)
+
+	conv := conversation.New(client.Core(), nil)
+	conv.SetMercuryClient(merc)
+	conv.SetEncryptionDeviceInfo(deviceURL, dev.GetDevice().UserID)
+	return conv, merc, dev, nil
+}
+
+// registerActivityHandler registers one conversation handler for every
+// activity; onActivity classifies each by verb, object type and target
+// type.
+func (l *Listener) registerActivityHandler() {
+	l.conversationClient.On(conversation.WildcardHandler, l.onActivity)
+}
+
+// onActivity classifies an activity and hands it to handleActivity,
+// dropping activities of ignored actors.
+func (l *Listener) onActivity(activity *conversation.Activity) {
+	if l.ignored(activity.Actor) {
+		return
+	}
+	resource, event := config.Classify(activityFields(activity))
+	l.invalidateMetadata(activity, resource, event)
+	l.handleActivity(activity, activity.Verb, resource, event)
+}
+
+// activityFields extracts the fields config.Classify looks at.
+func activityFields(activity *conversation.Activity) config.ActivityFields {
+	f := config.ActivityFields{Verb: activity.Verb}
+	if objectType, ok := activity.Object["objectType"].(string); ok {
+		f.ObjectType = objectType
+	}
+	if activity.Target != nil {
+		f.TargetType = activity.Target.ObjectType
+	}
+	if parentType, ok := rawParent(activity)["type"].(string); ok {
+		f.ParentType = parentType
+	}
+	if title, ok := activity.Object["displayName"].(string); ok && title != "" {
+		f.Titled = true
+	}
+	return f
+}
+
+// rawParent returns the activity's parent object from the raw Mercury
+// event, or nil. The SDK's Activity type does not carry it.
+func rawParent(activity *conversation.Activity) map[string]interface{} {
+	activityData, ok := activity.RawData["activity"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	parent, _ := activityData["parent"].(map[string]interface{})
+	return parent
+}
+
+// registerMercuryHandlers registers a Mercury handler for every event type
+// in config.MercuryEventTypes, such as typing, presence and Locus meeting
+// events, plus one for every event to track meeting states.
+func (l *Listener) registerMercuryHandlers(merc *mercury.Client) {
+	for eventType, mapping := range config.MercuryEventTypes {
 		// Capture loop variables for the closure
-		v := verb
+		t := eventType
 		m := mapping
 
-		l.conversationClient.On(v, func(activity *conversation.Activity) {
-			l.handleActivity(activity, v, m.Resource, m.Event)
+		merc.On(t, func(event *mercury.Event) {
+			l.handleMercuryEvent(event, t, m.Resource, m.Event)
 		})
 	}
+	merc.On("*", l.trackMeetingState)
+}
+
+// trackMeetingState emits meetings:started and meetings:ended when a Locus
+// event shows a meeting's state changing. An ended meeting is forgotten;
+// one first seen already ended is ignored.
+//
+// Meeting events come from Locus over Mercury rather than the SDK's
+// meetings client, which only wraps the REST meetings API and has no live
+// events.
+func (l *Listener) trackMeetingState(event *mercury.Event) {
+	eventType, _ := event.Data["eventType"].(string)
+	if !strings.HasPrefix(eventType, "locus.") {
+		return
+	}
+	locus, _ := event.Data["locus"].(map[string]interface{})
+	url, _ := locus["url"].(string)
+	fullState, _ := locus["fullState"].(map[string]interface{})
+	state, _ := fullState["state"].(string)
+	if url == "" || state == "" {
+		return
+	}
+
+	l.mu.Lock()
+	prev, seen := l.meetingStates[url]
+	name, known := config.MeetingStateEvents[state]
+	if name == "ended" {
+		delete(l.meetingStates, url)
+	} else {
+		l.meetingStates[url] = state
+	}
+	l.mu.Unlock()
+
+	if !known || config.MeetingStateEvents[prev] == name || (name == "ended" && !seen) {
+		return
+	}
+	if l.subscribed("meetings", name) {
+		l.publish("meetings", name, buildMercuryEventData(event, eventType), l.clusterOf(url))
+	}
+}
+
+// handleMercuryEvent forwards a non-activity Mercury event if its resource
+// and event are subscribed.
+func (l *Listener) handleMercuryEvent(event *mercury.Event, eventType, resource, name string) {
+	if !l.subscribed(resource, name) {
+		return
+	}
+	l.publish(resource, name, buildMercuryEventData(event, eventType), l.mercuryEventCluster(event))
+}
+
+// mercuryEventCluster returns the Hydra cluster of a non-activity Mercury
+// event from the service URL it carries: the conversation URL of typing
+// events or the locus URL of meeting events. Events without one, such as
+// presence, only carry people IDs, which are not cluster scoped.
+func (l *Listener) mercuryEventCluster(event *mercury.Event) string {
+	if u, ok := event.Data["conversationUrl"].(string); ok && u != "" {
+		return l.clusterOf(u)
+	}
+	locus, _ := event.Data["locus"].(map[string]interface{})
+	u, _ := locus["url"].(string)
+	return l.clusterOf(u)
 }
 
-// handleActivity is called when a conversation activity matches a
-// registered verb.  It checks the subscription filter and forwards
+// handleActivity is called with each classified conversation activity.
+// It checks the subscription filter and forwards
 // qualifying events to the target.
 func (l *Listener) handleActivity(activity *conversation.Activity, verb, resource, event string) {
-	// Check if the user is subscribed to this resource
+	if !l.subscribed(resource, event) {
+		return
+	}
+
+	// Build the data payload from the activity
+	data := buildEventData(activity, verb)
+	addResourceFields(data, resource, activity)
+	cluster := l.activityCluster(activity)
+	if resource == "messages" && activity.Verb == "share" {
+		l.addFiles(data, activity.ID, cluster)
+	}
+	if resource == "attachmentActions" {
+		l.addCardActionFields(data, activity, cluster)
+	}
+	l.publish(resource, event, data, cluster)
+}
+
+// publish enriches an event's data, converts its IDs to the listener's ID
+// form and delivers it. Every event, activity or not, goes through here.
+func (l *Listener) publish(resource, event string, data map[string]interface{}, cluster string) {
+	l.enrich(data, cluster)
+	l.convertIDs(data, resource, event, cluster)
+	l.deliver(resource, event, data)
+}
+
+// convertIDs rewrites data's identifiers to the listener's ID form.
+func (l *Listener) convertIDs(data map[string]interface{}, resource, event, cluster string) {
 	l.mu.Lock()
-	eventFilter, subscribed := l.subscriptions[resource]
+	form := l.idForm
 	l.mu.Unlock()
+	convertIDs(data, resource, event, form, cluster)
+}
 
-	if !subscribed {
-		return
+// activityCluster returns the Hydra cluster of an activity's room, taken
+// from the conversation URL.
+func (l *Listener) activityCluster(activity *conversation.Activity) string {
+	if activity.Target != nil && activity.Target.URL != "" {
+		return l.clusterOf(activity.Target.URL)
 	}
+	return l.clusterOf(activity.URL)
+}
 
-	// Check event filter
-	if eventFilter != "all" && eventFilter != event {
+// clusterOf returns the Hydra cluster of the resource at a service URL.
+func (l *Listener) clusterOf(serviceURL string) string {
+	l.mu.Lock()
+	clusters := l.clusters
+	l.mu.Unlock()
+	return clusters.ClusterOf(serviceURL)
+}
+
+// addFiles sets data.files to the message's inlined or stored files when
+// attachments are enabled. The message is looked up in the room's cluster.
+// Handlers run on their own goroutine, so the downloads do not hold up
+// other events.
+func (l *Listener) addFiles(data map[string]interface{}, activityID, cluster string) {
+	l.mu.Lock()
+	f := l.attachments
+	l.mu.Unlock()
+	if f == nil {
+		return
+	}
+	files, err := f.Fetch(activityID, cluster)
+	if err != nil {
+		display.Println(display.Error(fmt.Sprintf("[%s] attachments: %s", l.name, err.Error())))
 		return
 	}
+	data["files"] = files
+}
 
+// subscribed reports whether the user is subscribed to resource:event.
+func (l *Listener) subscribed(resource, event string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.subscriptions[resource][event]
+}
+
+// deliver logs an event and sends it to the observers and targets.
+func (l *Listener) deliver(resource, event string, data map[string]interface{}) {
 	// Log the received event
-	fmt.Println(display.Info(
+	display.Println(display.Info(
 		fmt.Sprintf("%s received",
 			display.Highlight(fmt.Sprintf("%s:%s", resource, event))),
 	))
 
-	// Build the data payload from the activity
-	data := buildEventData(activity, verb)
-
 	webhookEvent := config.WebhookEvent{
 		Resource:  resource,
 		Event:     event,
@@ -218,31 +641,38 @@ func (l *Listener) handleActivity(activity *conversation.Activity, verb, resourc
 		Timestamp: time.Now().UnixMilli(),
 	}
 
+	l.mu.Lock()
+	observers := l.observers
+	l.mu.Unlock()
+	for _, o := range observers {
+		o.Observe(l.name, webhookEvent)
+	}
+
 	// Forward asynchronously so we don't block the Mercury read loop
 	if l.balancer != nil {
 		// Round-robin mode: delegate to the balancer (retry + health checks)
 		go func() {
 			if err := l.balancer.Forward(webhookEvent); err != nil {
-				fmt.Println(display.Error(fmt.Sprintf("[%s] forward error: %s", l.name, err.Error())))
+				display.Println(display.Error(fmt.Sprintf("[%s] forward error: %s", l.name, err.Error())))
 			}
 		}()
-	} else if len(l.targets) > 0 {
+	} else if len(l.senders) > 0 {
 		// Fanout mode: send to all targets simultaneously
-		for _, t := range l.targets {
-			tgt := t
+		for _, s := range l.senders {
+			sender := s
 			go func() {
-				if err := forwarder.ForwardToURL(tgt.URL, webhookEvent); err != nil {
-					fmt.Println(display.Error(fmt.Sprintf("[%s] forward error: %s", l.name, err.Error())))
+				if err := sender.Send(webhookEvent); err != nil {
+					display.Println(display.Error(fmt.Sprintf("[%s] forward error: %s", l.name, err.Error())))
 				}
 			}()
 		}
-	} else {
+	} else if l.specs != nil {
 		// Legacy single-pipeline mode
 		target := l.specs.Target
 		port := l.specs.Port
 		go func() {
 			if err := forwarder.Forward(target, port, webhookEvent); err != nil {
-				fmt.Println(display.Error(fmt.Sprintf("forward error: %s", err.Error())))
+				display.Println(display.Error(fmt.Sprintf("forward error: %s", err.Error())))
 			}
 		}()
 	}
@@ -279,21 +709,96 @@ func buildEventData(activity *conversation.Activity, verb string) map[string]int
 	}
 
 	// Extract parentId from the raw activity data if present
-	if activity.RawData != nil {
-		if activityData, ok := activity.RawData["activity"].(map[string]interface{}); ok {
-			if parent, ok := activityData["parent"].(map[string]interface{}); ok {
-				if parentID, ok := parent["id"].(string); ok {
-					data["parentId"] = parentID
-				}
-			}
+	if parentID, ok := rawParent(activity)["id"].(string); ok {
+		data["parentId"] = parentID
+	}
+
+	return data
+}
+
+// buildMercuryEventData constructs the data payload for a non-activity
+// Mercury event, using the same field names as activity payloads where they
+// apply.
+func buildMercuryEventData(event *mercury.Event, eventType string) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":        event.ID,
+		"eventType": eventType,
+	}
+	if event.Timestamp > 0 {
+		data["published"] = time.UnixMilli(event.Timestamp).UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+
+	// Typing: {actor: {id}, conversationId}
+	if actor, ok := event.Data["actor"].(map[string]interface{}); ok {
+		if id, ok := actor["id"].(string); ok {
+			data["actorId"] = id
 		}
 	}
+	if roomID, ok := event.Data["conversationId"].(string); ok {
+		data["roomId"] = roomID
+	}
 
+	// Presence: {subject, status, lastActive, expiresTTL}
+	if personID, ok := event.Data["subject"].(string); ok {
+		data["personId"] = personID
+	}
+	for _, key := range []string{"status", "lastActive", "expiresTTL"} {
+		if v, ok := event.Data[key]; ok {
+			data[key] = v
+		}
+	}
+
+	// Meetings: {locus: {url, fullState: {state}, participants}}
+	if locus, ok := event.Data["locus"].(map[string]interface{}); ok {
+		data["locusUrl"] = locus["url"]
+		if fullState, ok := locus["fullState"].(map[string]interface{}); ok {
+			data["meetingState"] = fullState["state"]
+		}
+		if participants, ok := locus["participants"].([]interface{}); ok {
+			data["participantCount"] = len(participants)
+		}
+	}
 	return data
 }
 
-// Stop gracefully disconnects the Mercury WebSocket connection.
+// addResourceFields adds the fields specific to a resource's events:
+// thread metadata for messages, the new moderator flag for moderator
+// changes, the emoji and reacted-to message for reactions, and the last
+// message read for read receipts.
+func addResourceFields(data map[string]interface{}, resource string, activity *conversation.Activity) {
+	parent := rawParent(activity)
+	switch resource {
+	case "messages":
+		parentType, _ := parent["type"].(string)
+		data["isThreadReply"] = parentType == "reply"
+	case "memberships":
+		switch activity.Verb {
+		case "assignModerator":
+			data["isModerator"] = true
+		case "unassignModerator":
+			data["isModerator"] = false
+		}
+	case "readReceipts":
+		if messageID, ok := activity.Object["id"].(string); ok {
+			data["messageId"] = messageID
+		}
+	case "reactions":
+		if reaction, ok := activity.Object["displayName"].(string); ok {
+			data["reaction"] = reaction
+		}
+		if messageID, ok := parent["id"].(string); ok {
+			data["messageId"] = messageID
+		}
+	}
+}
+
+// Stop gracefully disconnects the Mercury WebSocket connection, deregisters
+// any calling line and closes the forwarding targets. The targets are
+// closed even when the listener never connected, and only once however
+// often Stop is called.
 func (l *Listener) Stop() error {
+	l.closeTargets()
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -304,22 +809,49 @@ func (l *Listener) Stop() error {
 	l.running = false
 
 	// Log each resource being stopped
-	prefix := ""
-	if l.name != "" {
-		prefix = fmt.Sprintf("[%s] ", l.name)
+	prefix := l.logPrefix()
+	resNames := make([]string, 0, len(l.subscriptions))
+	for resName := range l.subscriptions {
+		resNames = append(resNames, resName)
 	}
-	for resName, eventFilter := range l.subscriptions {
-		fmt.Println(display.Info(
-			fmt.Sprintf("%sstopping listener for %s:%s", prefix, resName, eventFilter),
+	sort.Strings(resNames)
+	for _, resName := range resNames {
+		var events []string
+		for _, ev := range config.Resources[resName].Events {
+			if l.subscriptions[resName][ev] {
+				events = append(events, ev)
+			}
+		}
+		display.Println(display.Info(
+			fmt.Sprintf("%sstopping listener for %s:%s", prefix, resName, strings.Join(events, ",")),
 		))
 	}
 
-	if l.balancer != nil {
-		l.balancer.Stop()
-	}
-
+	l.disconnectCalls()
 	if l.conversationClient != nil {
 		return l.conversationClient.Disconnect()
 	}
 	return nil
 }
+
+// closeTargets stops the balancer and closes the fanout senders, once.
+func (l *Listener) closeTargets() {
+	l.closeOnce.Do(func() {
+		if l.balancer != nil {
+			l.balancer.Stop()
+		}
+		for _, s := range l.senders {
+			if err := s.Close(); err != nil {
+				display.Println(display.Error(fmt.Sprintf("%serror closing target: %s", l.logPrefix(), err.Error())))
+			}
+		}
+	})
+}
+
+// logPrefix returns "[name] " for a named pipeline, or "" otherwise.
+func (l *Listener) logPrefix() string {
+	if l.name == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", l.name)
+}
@@ -1,9 +1,24 @@
 package listener
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 
+	"github.com/WebexCommunity/webex-go-sdk/v2/calling"
 	"github.com/WebexCommunity/webex-go-sdk/v2/conversation"
+	"github.com/WebexCommunity/webex-go-sdk/v2/mercury"
+	"github.com/WebexCommunity/webex-go-sdk/v2/messages"
+	"github.com/WebexCommunity/webex-go-sdk/v2/people"
+	"github.com/WebexCommunity/webex-go-sdk/v2/rooms"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/attachments"
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+	"github.com/tejzpr/webex-go-hookbuster/internal/metadata"
 )
 
 func TestBuildEventData_BasicFields(t *testing.T) {
@@ -171,3 +186,759 @@ func TestBuildEventData_MalformedRawData(t *testing.T) {
 		t.Error("parentId should not be present with malformed RawData")
 	}
 }
+
+// recordingObserver collects observed events.
+type recordingObserver struct {
+	pipelines []string
+	events    []config.WebhookEvent
+}
+
+func (r *recordingObserver) Observe(pipeline string, event config.WebhookEvent) {
+	r.pipelines = append(r.pipelines, pipeline)
+	r.events = append(r.events, event)
+}
+
+func TestHandleActivity_TargetlessPipelineNotifiesObservers(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["messages"] = map[string]bool{"created": true}
+
+	l.handleActivity(&conversation.Activity{ID: "msg-1"}, "post", "messages", "created")
+	l.handleActivity(&conversation.Activity{ID: "msg-2"}, "delete", "messages", "deleted")
+
+	if len(obs.events) != 1 {
+		t.Fatalf("observed %d events, want 1", len(obs.events))
+	}
+	if obs.pipelines[0] != "dash" || obs.events[0].Resource != "messages" || obs.events[0].Event != "created" {
+		t.Errorf("observed %s %s:%s, want dash messages:created",
+			obs.pipelines[0], obs.events[0].Resource, obs.events[0].Event)
+	}
+}
+
+func TestExpandEvents(t *testing.T) {
+	memberships := config.Resources["memberships"]
+	tests := []struct {
+		events  []string
+		want    string
+		wantErr bool
+	}{
+		{nil, "created,updated,deleted,moderatorAssigned,moderatorUnassigned", false},
+		{[]string{"all"}, "created,updated,deleted,moderatorAssigned,moderatorUnassigned", false},
+		{[]string{"deleted", "created", "deleted"}, "created,deleted", false},
+		{[]string{"created", "all"}, "created,updated,deleted,moderatorAssigned,moderatorUnassigned", false},
+		{[]string{"create"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := expandEvents(memberships, tt.events)
+		if (err != nil) != tt.wantErr || strings.Join(got, ",") != tt.want {
+			t.Errorf("expandEvents(%v) = %v, %v; want %s (error %v)", tt.events, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// attachmentActions has no "all" event but still accepts it
+	if got, err := expandEvents(config.Resources["attachmentActions"], []string{"all"}); err != nil || len(got) != 1 {
+		t.Errorf("expandEvents(attachmentActions, all) = %v, %v", got, err)
+	}
+}
+
+func TestHandleActivity_EventSet(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["memberships"] = map[string]bool{"created": true, "deleted": true}
+
+	l.handleActivity(&conversation.Activity{ID: "m-1"}, "add", "memberships", "created")
+	l.handleActivity(&conversation.Activity{ID: "m-2"}, "assignModerator", "memberships", "updated")
+	l.handleActivity(&conversation.Activity{ID: "m-3"}, "leave", "memberships", "deleted")
+	l.handleActivity(&conversation.Activity{ID: "r-1"}, "create", "rooms", "created")
+
+	var got []string
+	for _, e := range obs.events {
+		got = append(got, e.Resource+":"+e.Event)
+	}
+	if strings.Join(got, ",") != "memberships:created,memberships:deleted" {
+		t.Errorf("observed %v, want memberships:created and memberships:deleted", got)
+	}
+}
+
+// recordedActivity builds an Activity the way the SDK does from a Mercury
+// "conversation.activity" event, keeping the raw event data.
+func recordedActivity(t *testing.T, raw string) *conversation.Activity {
+	t.Helper()
+	var activityData map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &activityData); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	var activity conversation.Activity
+	if err := json.Unmarshal([]byte(raw), &activity); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	activity.RawData = map[string]interface{}{"activity": activityData}
+	return &activity
+}
+
+func TestOnActivity_ClassifiesRecordedActivities(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "message",
+			raw:  `{"id":"a1","verb":"post","object":{"objectType":"comment"},"target":{"id":"r1","objectType":"conversation"}}`,
+			want: "messages:created",
+		},
+		{
+			name: "message edit",
+			raw:  `{"id":"a2","verb":"post","object":{"objectType":"comment"},"target":{"id":"r1","objectType":"conversation"},"parent":{"id":"a1","type":"edit"}}`,
+			want: "messages:updated",
+		},
+		{
+			name: "room rename",
+			raw:  `{"id":"a3","verb":"update","object":{"objectType":"conversation","displayName":"New title"},"target":{"id":"r1","objectType":"conversation"}}`,
+			want: "rooms:renamed",
+		},
+		{
+			name: "room settings update",
+			raw:  `{"id":"a6","verb":"update","object":{"objectType":"conversation"},"target":{"id":"r1","objectType":"conversation"}}`,
+			want: "rooms:updated",
+		},
+		{
+			name: "team rename",
+			raw:  `{"id":"a4","verb":"update","object":{"objectType":"team","displayName":"New team"},"target":{"id":"t1","objectType":"team"}}`,
+			want: "teams:updated",
+		},
+		{
+			name: "team member added",
+			raw:  `{"id":"a5","verb":"add","object":{"objectType":"person","id":"p1"},"target":{"id":"t1","objectType":"team"}}`,
+			want: "unclassified:received",
+		},
+	}
+	for _, tt := range tests {
+		l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+		if err != nil {
+			t.Fatalf("NewPipelineListener() error: %v", err)
+		}
+		obs := &recordingObserver{}
+		l.AddObserver(obs)
+		resource, event, _ := strings.Cut(tt.want, ":")
+		l.subscriptions[resource] = map[string]bool{event: true}
+
+		l.onActivity(recordedActivity(t, tt.raw))
+
+		if len(obs.events) != 1 {
+			t.Errorf("%s: observed %d events, want %s", tt.name, len(obs.events), tt.want)
+			continue
+		}
+		data := obs.events[0].Data.(map[string]interface{})
+		if data["verb"] == "" {
+			t.Errorf("%s: event data is missing the verb", tt.name)
+		}
+	}
+}
+
+func TestOnActivity_ThreadAndReactionFields(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]interface{}
+	}{
+		{
+			name: "thread reply",
+			raw:  `{"id":"a1","verb":"post","object":{"objectType":"comment"},"target":{"id":"r1","objectType":"conversation"},"parent":{"id":"root-1","type":"reply"}}`,
+			want: map[string]interface{}{"parentId": "root-1", "isThreadReply": true},
+		},
+		{
+			name: "top-level message",
+			raw:  `{"id":"a2","verb":"post","object":{"objectType":"comment"},"target":{"id":"r1","objectType":"conversation"}}`,
+			want: map[string]interface{}{"isThreadReply": false},
+		},
+		{
+			name: "moderator assigned",
+			raw:  `{"id":"a4","verb":"assignModerator","object":{"objectType":"person","id":"p1"},"target":{"id":"r1","objectType":"conversation"}}`,
+			want: map[string]interface{}{"isModerator": true},
+		},
+		{
+			name: "reaction",
+			raw:  `{"id":"a3","verb":"add","object":{"objectType":"reaction2","displayName":"heart"},"target":{"id":"r1","objectType":"conversation"},"parent":{"id":"msg-9","type":"reaction"}}`,
+			want: map[string]interface{}{"reaction": "heart", "messageId": "msg-9"},
+		},
+	}
+	for _, tt := range tests {
+		l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+		if err != nil {
+			t.Fatalf("NewPipelineListener() error: %v", err)
+		}
+		obs := &recordingObserver{}
+		l.AddObserver(obs)
+		l.subscriptions["messages"] = map[string]bool{"created": true}
+		l.subscriptions["reactions"] = map[string]bool{"created": true}
+		l.subscriptions["memberships"] = map[string]bool{"moderatorAssigned": true}
+
+		l.onActivity(recordedActivity(t, tt.raw))
+
+		if len(obs.events) != 1 {
+			t.Errorf("%s: observed %d events, want 1", tt.name, len(obs.events))
+			continue
+		}
+		data := obs.events[0].Data.(map[string]interface{})
+		for k, v := range tt.want {
+			if data[k] != v {
+				t.Errorf("%s: data[%q] = %v, want %v", tt.name, k, data[k], v)
+			}
+		}
+	}
+}
+
+// fileSource serves one text file for every message.
+type fileSource struct{}
+
+func (fileSource) MessageFiles(string) ([]string, error) {
+	return []string{"https://webexapis.com/v1/contents/f1"}, nil
+}
+
+func (fileSource) Download(string) (*attachments.Download, error) {
+	return &attachments.Download{ContentType: "text/plain", ContentDisposition: `attachment; filename="a.txt"`, ContentLength: 2, Body: io.NopCloser(strings.NewReader("hi"))}, nil
+}
+
+func TestOnActivity_Attachments(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["messages"] = map[string]bool{"created": true}
+	l.attachments, err = attachments.New(&config.AttachmentOptions{Mode: config.AttachmentsInline}, fileSource{})
+	if err != nil {
+		t.Fatalf("attachments.New() error: %v", err)
+	}
+
+	target := &conversation.Target{ID: "r1", ObjectType: "conversation"}
+	l.onActivity(&conversation.Activity{ID: "m1", Verb: "share", Object: map[string]interface{}{"objectType": "content"}, Target: target})
+	l.onActivity(&conversation.Activity{ID: "m2", Verb: "post", Object: map[string]interface{}{"objectType": "comment"}, Target: target})
+
+	if len(obs.events) != 2 {
+		t.Fatalf("observed %d events, want 2", len(obs.events))
+	}
+	files, ok := obs.events[0].Data.(map[string]interface{})["files"].([]attachments.File)
+	if !ok || len(files) != 1 || files[0].Name != "a.txt" || files[0].Data != "aGk=" {
+		t.Errorf("share files = %#v", obs.events[0].Data.(map[string]interface{})["files"])
+	}
+	if _, ok := obs.events[1].Data.(map[string]interface{})["files"]; ok {
+		t.Error("post without files got data.files")
+	}
+}
+
+func TestOnActivity_CardAction(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["attachmentActions"] = map[string]bool{"created": true}
+
+	var gotKey, gotMessage string
+	l.decryptText = func(keyURL, ciphertext string) (string, error) {
+		gotKey = keyURL
+		return `{"choice":"yes"}`, nil
+	}
+	card := map[string]interface{}{"type": "AdaptiveCard"}
+	l.getMessage = func(id string) (*messages.Message, error) {
+		gotMessage = id
+		return &messages.Message{Attachments: []messages.Attachment{{ContentType: adaptiveCardContentType, Content: card}}}, nil
+	}
+
+	l.onActivity(&conversation.Activity{
+		ID:               "a1",
+		Verb:             "cardAction",
+		Published:        "2026-02-07T02:08:14.939Z",
+		EncryptionKeyURL: "kms://kms.example.com/keys/1",
+		Actor:            &conversation.Actor{ID: "p1"},
+		Target:           &conversation.Target{ID: "r1", ObjectType: "conversation"},
+		Object:           map[string]interface{}{"objectType": "submit", "inputs": "eyJhbGciOiJkaXIifQ..x.y.z"},
+		RawData: map[string]interface{}{"activity": map[string]interface{}{
+			"parent": map[string]interface{}{"id": "92db3be0-43bd-11e6-8ae9-dd5b3dfc565d", "type": "cardAction"},
+		}},
+	})
+
+	if len(obs.events) != 1 {
+		t.Fatalf("observed %d events, want 1", len(obs.events))
+	}
+	data := obs.events[0].Data.(map[string]interface{})
+	want := map[string]interface{}{
+		"type":      "submit",
+		"personId":  "p1",
+		"roomId":    "r1",
+		"created":   "2026-02-07T02:08:14.939Z",
+		"messageId": "92db3be0-43bd-11e6-8ae9-dd5b3dfc565d",
+	}
+	for k, v := range want {
+		if data[k] != v {
+			t.Errorf("data[%q] = %v, want %v", k, data[k], v)
+		}
+	}
+	if inputs, _ := data["inputs"].(map[string]interface{}); inputs["choice"] != "yes" {
+		t.Errorf("inputs = %v", data["inputs"])
+	}
+	if got, _ := data["card"].(map[string]interface{}); got["type"] != "AdaptiveCard" {
+		t.Errorf("card = %v", data["card"])
+	}
+	if gotKey != "kms://kms.example.com/keys/1" {
+		t.Errorf("decrypted with key %q", gotKey)
+	}
+	if gotMessage != "Y2lzY29zcGFyazovL3VzL01FU1NBR0UvOTJkYjNiZTAtNDNiZC0xMWU2LThhZTktZGQ1YjNkZmM1NjVk" {
+		t.Errorf("looked up message %q, want its REST id", gotMessage)
+	}
+}
+
+func TestOnActivity_IgnoredActors(t *testing.T) {
+	l, err := NewPipelineListener("bot", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["messages"] = map[string]bool{"created": true}
+	// The bot's own REST person ID, and another bot by email
+	l.IgnoreActors("Y2lzY29zcGFyazovL3VzL1BFT1BMRS9mNWIzNjE4Ny1jOGRkLTQ3MjctOGIyZi1mOWM0NDdmMjkwNDY", "Other-Bot@webex.bot")
+
+	post := func(actor conversation.Actor) {
+		l.onActivity(&conversation.Activity{
+			ID:     "m-" + actor.ID,
+			Verb:   "post",
+			Actor:  &actor,
+			Object: map[string]interface{}{"objectType": "comment"},
+			Target: &conversation.Target{ID: "r1", ObjectType: "conversation"},
+		})
+	}
+	post(conversation.Actor{ID: "f5b36187-c8dd-4727-8b2f-f9c447f29046"})
+	post(conversation.Actor{ID: "b2", EmailAddress: "other-bot@webex.bot"})
+	post(conversation.Actor{ID: "u1", EmailAddress: "alice@example.com"})
+
+	if len(obs.events) != 1 {
+		t.Fatalf("observed %d events, want 1", len(obs.events))
+	}
+	if id := obs.events[0].Data.(map[string]interface{})["actorId"]; id != "u1" {
+		t.Errorf("forwarded actor = %v, want u1", id)
+	}
+}
+
+func TestConvertIDs(t *testing.T) {
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"id":         "m1",
+			"roomId":     "r1",
+			"actorId":    "p1",
+			"actorOrgId": "o1",
+			"parentId":   "m0",
+			"object":     map[string]interface{}{"id": "m0"},
+		}
+	}
+	tests := []struct {
+		name     string
+		resource string
+		event    string
+		form     string
+		want     map[string]interface{}
+	}{
+		{
+			name: "uuid form", resource: "messages", event: "created", form: config.IDsUUID,
+			want: map[string]interface{}{"id": "m1", "roomId": "r1", "actorId": "p1"},
+		},
+		{
+			name: "hydra form", resource: "messages", event: "created", form: config.IDsHydra,
+			want: map[string]interface{}{
+				"id":         hydra.ID(hydra.Message, "m1"),
+				"roomId":     hydra.ID(hydra.Room, "r1"),
+				"actorId":    hydra.ID(hydra.People, "p1"),
+				"actorOrgId": hydra.ID(hydra.Organization, "o1"),
+				"parentId":   hydra.ID(hydra.Message, "m0"),
+				"uuid":       nil,
+			},
+		},
+		{
+			name: "both forms", resource: "messages", event: "created", form: config.IDsBoth,
+			want: map[string]interface{}{
+				"id":        hydra.ID(hydra.Message, "m1"),
+				"uuid":      "m1",
+				"roomId":    hydra.ID(hydra.Room, "r1"),
+				"roomUuid":  "r1",
+				"actorUuid": "p1",
+			},
+		},
+		{
+			name: "activity id has no REST form", resource: "messages", event: "deleted", form: config.IDsBoth,
+			want: map[string]interface{}{"id": "m1", "uuid": nil, "roomUuid": "r1"},
+		},
+	}
+	for _, tt := range tests {
+		data := base()
+		convertIDs(data, tt.resource, tt.event, tt.form, hydra.DefaultCluster)
+		for k, v := range tt.want {
+			if got, ok := data[k]; (v == nil && ok) || (v != nil && got != v) {
+				t.Errorf("%s: data[%q] = %v, want %v", tt.name, k, got, v)
+			}
+		}
+		if obj := data["object"].(map[string]interface{}); obj["id"] != "m0" {
+			t.Errorf("%s: object was rewritten: %v", tt.name, obj)
+		}
+	}
+}
+
+// euHostMap is a device serviceHostMap placing conv-k.wbx2.com in the EU.
+var euHostMap = map[string]interface{}{
+	"hostCatalog": map[string]interface{}{
+		"conv-k.wbx2.com": []interface{}{
+			map[string]interface{}{"host": "conv-k.wbx2.com", "id": "urn:TEAM:eu-central-1_k:conversation"},
+		},
+	},
+}
+
+// messageFileSource records the message ID files are looked up with.
+type messageFileSource struct{ messageID string }
+
+func (s *messageFileSource) MessageFiles(messageID string) ([]string, error) {
+	s.messageID = messageID
+	return nil, nil
+}
+
+func (s *messageFileSource) Download(string) (*attachments.Download, error) {
+	return nil, errors.New("no files")
+}
+
+func TestOnActivity_HydraIDsUseRoomCluster(t *testing.T) {
+	const eu = "urn:TEAM:eu-central-1_k"
+	l, err := NewPipelineListener("eu", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["messages"] = map[string]bool{"created": true}
+	l.subscriptions["attachmentActions"] = map[string]bool{"created": true}
+	l.SetIDForm(config.IDsHydra)
+	l.clusters = hydra.NewResolver(euHostMap)
+	files := &messageFileSource{}
+	l.attachments, err = attachments.New(&config.AttachmentOptions{Mode: config.AttachmentsInline}, files)
+	if err != nil {
+		t.Fatalf("attachments.New() error: %v", err)
+	}
+	var cardMessage string
+	l.getMessage = func(id string) (*messages.Message, error) {
+		cardMessage = id
+		return &messages.Message{}, nil
+	}
+
+	target := &conversation.Target{ID: "r1", ObjectType: "conversation", URL: "https://conv-k.wbx2.com/conversation/api/v1/conversations/r1"}
+	l.onActivity(&conversation.Activity{
+		ID:     "m1",
+		Verb:   "share",
+		Actor:  &conversation.Actor{ID: "p1", OrgID: "o1"},
+		Object: map[string]interface{}{"objectType": "content"},
+		Target: target,
+	})
+	l.onActivity(&conversation.Activity{
+		ID:     "a1",
+		Verb:   "cardAction",
+		Object: map[string]interface{}{"objectType": "submit"},
+		Target: target,
+		RawData: map[string]interface{}{"activity": map[string]interface{}{
+			"parent": map[string]interface{}{"id": "m0", "type": "cardAction"},
+		}},
+	})
+
+	if len(obs.events) != 2 {
+		t.Fatalf("observed %d events, want 2", len(obs.events))
+	}
+	data := obs.events[0].Data.(map[string]interface{})
+	if want := hydra.IDIn(eu, hydra.Room, "r1"); data["roomId"] != want {
+		t.Errorf("roomId = %v, want %v", data["roomId"], want)
+	}
+	if want := hydra.ID(hydra.People, "p1"); data["actorId"] != want {
+		t.Errorf("actorId = %v, want the global %v", data["actorId"], want)
+	}
+	if want := hydra.ID(hydra.Organization, "o1"); data["actorOrgId"] != want {
+		t.Errorf("actorOrgId = %v, want the global %v", data["actorOrgId"], want)
+	}
+	if want := hydra.IDIn(eu, hydra.Message, "m1"); files.messageID != want {
+		t.Errorf("files looked up with %q, want %q", files.messageID, want)
+	}
+	if want := hydra.IDIn(eu, hydra.Message, "m0"); cardMessage != want {
+		t.Errorf("card looked up with %q, want %q", cardMessage, want)
+	}
+}
+
+// fakeMetadataSource serves one room and one person and counts lookups.
+type fakeMetadataSource struct {
+	calls int
+}
+
+func (s *fakeMetadataSource) Room(id string) (*rooms.Room, error) {
+	s.calls++
+	return &rooms.Room{Title: "Planning", Type: "direct", TeamID: hydra.ID(hydra.Team, "t1")}, nil
+}
+
+func (s *fakeMetadataSource) Person(id string) (*people.Person, error) {
+	s.calls++
+	return &people.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}, OrgID: hydra.ID(hydra.Organization, "o1")}, nil
+}
+
+func TestOnActivity_Enrich(t *testing.T) {
+	l, err := NewPipelineListener("enrich", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["messages"] = map[string]bool{"created": true}
+	src := &fakeMetadataSource{}
+	l.metadata = metadata.New(nil)
+	l.metadataSource = src
+
+	post := &conversation.Activity{
+		ID:     "m1",
+		Verb:   "post",
+		Actor:  &conversation.Actor{ID: "p1"},
+		Object: map[string]interface{}{"objectType": "comment"},
+		Target: &conversation.Target{ID: "r1", ObjectType: "conversation"},
+	}
+	l.onActivity(post)
+	l.onActivity(post)
+
+	if len(obs.events) != 2 {
+		t.Fatalf("observed %d events, want 2", len(obs.events))
+	}
+	data := obs.events[1].Data.(map[string]interface{})
+	if data["roomTitle"] != "Planning" || data["roomType"] != "direct" || data["isDirect"] != true || data["teamId"] != "t1" {
+		t.Errorf("room fields = %v %v %v %v", data["roomTitle"], data["roomType"], data["isDirect"], data["teamId"])
+	}
+	if data["actorDisplayName"] != "Alice" || data["actorEmail"] != "alice@example.com" || data["actorOrgId"] != "o1" {
+		t.Errorf("actor fields = %v %v %v", data["actorDisplayName"], data["actorEmail"], data["actorOrgId"])
+	}
+	if src.calls != 2 {
+		t.Errorf("lookups = %d, want 2 (one room, one person)", src.calls)
+	}
+
+	// A membership change in the room, though not subscribed, drops the
+	// cached details of the room and of the member.
+	l.onActivity(&conversation.Activity{
+		ID:     "a1",
+		Verb:   "add",
+		Actor:  &conversation.Actor{ID: "p2"},
+		Object: map[string]interface{}{"objectType": "person", "id": "p1"},
+		Target: &conversation.Target{ID: "r1", ObjectType: "conversation"},
+	})
+	l.onActivity(post)
+	if src.calls != 4 {
+		t.Errorf("lookups = %d after a membership change, want 4", src.calls)
+	}
+}
+
+func TestCardInputs(t *testing.T) {
+	l := &Listener{}
+	tests := []struct {
+		name    string
+		inputs  interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"object", map[string]interface{}{"a": "1"}, "1", ""},
+		{"plain json", `{"a":"1"}`, "1", ""},
+		{"encrypted without key", "eyJhbGciOiJkaXIifQ..x.y.z", nil, "no key is available"},
+		{"missing", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		activity := &conversation.Activity{Object: map[string]interface{}{}}
+		if tt.inputs != nil {
+			activity.Object["inputs"] = tt.inputs
+		}
+		inputs, err := l.cardInputs(activity)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: error: %v", tt.name, err)
+			continue
+		}
+		if tt.want == nil {
+			if inputs != nil {
+				t.Errorf("%s: inputs = %v, want nil", tt.name, inputs)
+			}
+			continue
+		}
+		if inputs["a"] != tt.want {
+			t.Errorf("%s: inputs = %v", tt.name, inputs)
+		}
+	}
+}
+
+func TestHandleMercuryEvent(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["typing"] = map[string]bool{"started": true}
+	l.subscriptions["presence"] = map[string]bool{"updated": true}
+
+	typing := &mercury.Event{ID: "e1", Timestamp: 1700000000000, Data: map[string]interface{}{
+		"eventType":      "status.start_typing",
+		"actor":          map[string]interface{}{"id": "p1"},
+		"conversationId": "r1",
+	}}
+	stopped := &mercury.Event{ID: "e2", Data: map[string]interface{}{"eventType": "status.stop_typing"}}
+	presence := &mercury.Event{ID: "e3", Data: map[string]interface{}{
+		"eventType": "apheleia.subscription_update",
+		"subject":   "p2",
+		"status":    "dnd",
+	}}
+	l.handleMercuryEvent(typing, "status.start_typing", "typing", "started")
+	l.handleMercuryEvent(stopped, "status.stop_typing", "typing", "stopped")
+	l.handleMercuryEvent(presence, "apheleia.subscription_update", "presence", "updated")
+
+	if len(obs.events) != 2 {
+		t.Fatalf("observed %d events, want 2", len(obs.events))
+	}
+	data := obs.events[0].Data.(map[string]interface{})
+	if obs.events[0].Resource != "typing" || data["actorId"] != "p1" || data["roomId"] != "r1" || data["published"] != "2023-11-14T22:13:20.000Z" {
+		t.Errorf("typing event = %s:%s %v", obs.events[0].Resource, obs.events[0].Event, data)
+	}
+	data = obs.events[1].Data.(map[string]interface{})
+	if obs.events[1].Resource != "presence" || data["personId"] != "p2" || data["status"] != "dnd" {
+		t.Errorf("presence event = %s:%s %v", obs.events[1].Resource, obs.events[1].Event, data)
+	}
+}
+
+// locusEvent builds a Locus Mercury event for a meeting in state.
+func locusEvent(eventType, url, state string, participants int) *mercury.Event {
+	list := make([]interface{}, participants)
+	return &mercury.Event{ID: eventType, Data: map[string]interface{}{
+		"eventType": eventType,
+		"locus": map[string]interface{}{
+			"url":          url,
+			"fullState":    map[string]interface{}{"state": state},
+			"participants": list,
+		},
+	}}
+}
+
+func TestTrackMeetingState(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["meetings"] = map[string]bool{"started": true, "ended": true}
+
+	steps := []*mercury.Event{
+		locusEvent("locus.difference", "loci/old", "INACTIVE", 0), // first seen ended: ignored
+		locusEvent("locus.difference", "loci/1", "INITIALIZING", 1),
+		locusEvent("locus.participant_joined", "loci/1", "ACTIVE", 1),
+		locusEvent("locus.participant_joined", "loci/1", "ACTIVE", 2),
+		locusEvent("locus.difference", "loci/1", "TERMINATING", 0),
+		locusEvent("locus.difference", "loci/1", "INACTIVE", 0),
+		{ID: "other", Data: map[string]interface{}{"eventType": "status.start_typing"}},
+	}
+	for _, e := range steps {
+		l.trackMeetingState(e)
+	}
+
+	var got []string
+	for _, e := range obs.events {
+		got = append(got, e.Event)
+	}
+	if strings.Join(got, ",") != "started,ended" {
+		t.Fatalf("observed %v, want started,ended", got)
+	}
+	data := obs.events[0].Data.(map[string]interface{})
+	if data["locusUrl"] != "loci/1" || data["meetingState"] != "ACTIVE" || data["participantCount"] != 1 {
+		t.Errorf("started data = %v", data)
+	}
+}
+
+func TestWatchCall(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeRoundRobin, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	obs := &recordingObserver{}
+	l.AddObserver(obs)
+	l.subscriptions["calls"] = map[string]bool{"incoming": true, "answered": true, "disconnected": true}
+
+	call, err := calling.NewCall(nil, calling.CallDirectionInbound, nil, &calling.CallConfig{})
+	if err != nil {
+		t.Fatalf("NewCall() error: %v", err)
+	}
+	l.watchCall(call)
+	mobius := func(eventType calling.MobiusEventType, callerID *calling.CallerIDInfo) {
+		call.HandleMobiusEvent(&calling.MobiusCallEvent{Data: calling.MobiusCallData{
+			EventType: eventType,
+			CallID:    call.GetCallID(),
+			CallerID:  callerID,
+		}})
+	}
+	mobius(calling.MobiusEventCallConnected, &calling.CallerIDInfo{From: "sip:alice@example.com"})
+	mobius(calling.MobiusEventCallConnected, nil) // repeated: not published again
+	mobius(calling.MobiusEventCallDisconnected, nil)
+
+	var got []string
+	for _, e := range obs.events {
+		if e.Resource != "calls" {
+			t.Errorf("resource = %q, want calls", e.Resource)
+		}
+		got = append(got, e.Event)
+	}
+	if strings.Join(got, ",") != "incoming,answered,disconnected" {
+		t.Fatalf("observed %v, want incoming,answered,disconnected", got)
+	}
+	incoming := obs.events[0].Data.(map[string]interface{})
+	if incoming["callId"] != call.GetCallID() || incoming["correlationId"] != call.GetCorrelationID() || incoming["direction"] != "inbound" {
+		t.Errorf("incoming data = %v", incoming)
+	}
+	if _, ok := incoming["callerId"]; ok {
+		t.Errorf("incoming data has callerId before Mobius sent one: %v", incoming)
+	}
+	ended := obs.events[2].Data.(map[string]interface{})
+	if ended["callState"] != "disconnected" || ended["callerId"] != "sip:alice@example.com" {
+		t.Errorf("disconnected data = %v", ended)
+	}
+}
+
+// closeCounter is a forwarder.Sender that counts Close calls.
+type closeCounter struct{ closed int }
+
+func (c *closeCounter) Send(config.WebhookEvent) error { return nil }
+func (c *closeCounter) Probe() error                   { return nil }
+func (c *closeCounter) Close() error                   { c.closed++; return nil }
+
+func TestStop_ClosesSendersWhenNotRunning(t *testing.T) {
+	l, err := NewPipelineListener("dash", "token", config.ModeFanout, nil)
+	if err != nil {
+		t.Fatalf("NewPipelineListener() error: %v", err)
+	}
+	sender := &closeCounter{}
+	l.senders = []forwarder.Sender{sender}
+
+	for i := 0; i < 2; i++ {
+		if err := l.Stop(); err != nil {
+			t.Fatalf("Stop() error: %v", err)
+		}
+	}
+	if sender.closed != 1 {
+		t.Errorf("Close() called %d times, want 1", sender.closed)
+	}
+}
@@ -0,0 +1,391 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package loadgen synthesizes webhook events at a fixed rate and drives them
+// through forwarding sinks, measuring throughput, latency and errors. It is
+// used by "hookbuster loadgen" to benchmark receivers and the forwarding path
+// without a Webex connection.
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// Kind is a resource/event pair, e.g. messages:created.
+type Kind struct {
+	Resource string
+	Event    string
+}
+
+func (k Kind) String() string {
+	return k.Resource + ":" + k.Event
+}
+
+// AllKinds returns every concrete resource/event pair in config.Resources,
+// sorted by name.
+func AllKinds() []Kind {
+	var kinds []Kind
+	for name, res := range config.Resources {
+		for _, ev := range res.Events {
+			if ev != "all" {
+				kinds = append(kinds, Kind{Resource: name, Event: ev})
+			}
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// Mix is a weighted distribution over event kinds.
+type Mix struct {
+	kinds      []Kind
+	cumulative []float64
+}
+
+// UniformMix weights every kind in AllKinds equally.
+func UniformMix() *Mix {
+	m := &Mix{}
+	for _, k := range AllKinds() {
+		m.add(k, 1)
+	}
+	return m
+}
+
+// ParseMix parses a distribution such as
+// "messages:created=70,memberships=20,rooms:updated=10". A bare resource
+// spreads its weight evenly over that resource's events. An empty spec is
+// the uniform mix.
+func ParseMix(spec string) (*Mix, error) {
+	if strings.TrimSpace(spec) == "" {
+		return UniformMix(), nil
+	}
+
+	m := &Mix{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weightStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("mix entry %q must be kind=weight", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("mix entry %q: weight must be a non-negative number", part)
+		}
+
+		resName, event, hasEvent := strings.Cut(strings.TrimSpace(name), ":")
+		res, ok := config.Resources[resName]
+		if !ok {
+			return nil, fmt.Errorf("mix entry %q: unknown resource %q", part, resName)
+		}
+
+		var kinds []Kind
+		for _, ev := range res.Events {
+			if ev == "all" || (hasEvent && ev != event) {
+				continue
+			}
+			kinds = append(kinds, Kind{Resource: resName, Event: ev})
+		}
+		if len(kinds) == 0 {
+			return nil, fmt.Errorf("mix entry %q: resource %s has no event %q", part, resName, event)
+		}
+		for _, k := range kinds {
+			m.add(k, weight/float64(len(kinds)))
+		}
+	}
+
+	if len(m.kinds) == 0 || m.total() == 0 {
+		return nil, fmt.Errorf("mix %q has no positive weights", spec)
+	}
+	return m, nil
+}
+
+func (m *Mix) add(k Kind, weight float64) {
+	if weight <= 0 {
+		return
+	}
+	m.kinds = append(m.kinds, k)
+	m.cumulative = append(m.cumulative, m.total()+weight)
+}
+
+func (m *Mix) total() float64 {
+	if len(m.cumulative) == 0 {
+		return 0
+	}
+	return m.cumulative[len(m.cumulative)-1]
+}
+
+// Kinds returns the kinds the mix can produce.
+func (m *Mix) Kinds() []Kind {
+	return m.kinds
+}
+
+// Pick draws a kind according to the weights.
+func (m *Mix) Pick(r *rand.Rand) Kind {
+	x := r.Float64() * m.total()
+	i := sort.SearchFloat64s(m.cumulative, x)
+	if i >= len(m.kinds) {
+		i = len(m.kinds) - 1
+	}
+	return m.kinds[i]
+}
+
+// kindVerbs maps each kind to the Mercury verb of its first rule in
+// config.ActivityRules, so output is stable.
+var kindVerbs = func() map[Kind]string {
+	verbs := make(map[Kind]string)
+	for _, r := range config.ActivityRules {
+		k := Kind{Resource: r.Resource, Event: r.Event}
+		if _, ok := verbs[k]; !ok {
+			verbs[k] = r.Verb
+		}
+	}
+	return verbs
+}()
+
+// Synthesize builds an event of kind k shaped like the listener's payloads.
+// seq makes IDs unique within a run.
+func Synthesize(k Kind, seq int64, now time.Time) config.WebhookEvent {
+	id := fmt.Sprintf("loadgen-%d", seq)
+	roomID := fmt.Sprintf("loadgen-room-%d", seq%10)
+	data := map[string]interface{}{
+		"id":               id,
+		"verb":             kindVerbs[k],
+		"actorId":          "loadgen-actor",
+		"actorDisplayName": "Load Generator",
+		"actorEmail":       "loadgen@hookbuster.local",
+		"actorOrgId":       "loadgen-org",
+		"roomId":           roomID,
+		"published":        now.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+
+	switch k.Resource {
+	case "messages":
+		if k.Event == "created" || k.Event == "updated" {
+			data["content"] = fmt.Sprintf("synthetic message %d", seq)
+			data["object"] = map[string]interface{}{"objectType": "comment"}
+		}
+		if k.Event == "updated" {
+			data["parentId"] = fmt.Sprintf("loadgen-%d", seq-1)
+		}
+		data["isThreadReply"] = false
+	case "memberships":
+		data["object"] = map[string]interface{}{"objectType": "person", "id": fmt.Sprintf("loadgen-person-%d", seq%100)}
+		if strings.HasPrefix(k.Event, "moderator") {
+			data["isModerator"] = k.Event == "moderatorAssigned"
+		}
+	case "rooms":
+		data["object"] = map[string]interface{}{"objectType": "conversation", "id": roomID}
+		if k.Event == "renamed" {
+			data["object"].(map[string]interface{})["displayName"] = fmt.Sprintf("synthetic room %d", seq)
+		}
+	case "teams":
+		data["object"] = map[string]interface{}{"objectType": "team", "id": fmt.Sprintf("loadgen-team-%d", seq%10)}
+	case "attachmentActions":
+		inputs := map[string]interface{}{"choice": "yes"}
+		data["parentId"] = fmt.Sprintf("loadgen-card-%d", seq%10)
+		data["messageId"] = data["parentId"]
+		data["type"] = "submit"
+		data["inputs"] = inputs
+		data["personId"] = data["actorId"]
+		data["created"] = data["published"]
+		data["object"] = map[string]interface{}{"objectType": "submit", "inputs": inputs}
+	case "reactions":
+		data["messageId"] = fmt.Sprintf("loadgen-%d", seq-1)
+		data["reaction"] = "thumbsup"
+		data["object"] = map[string]interface{}{"objectType": "reaction2", "displayName": "thumbsup"}
+	case "readReceipts":
+		data["messageId"] = fmt.Sprintf("loadgen-%d", seq-1)
+		data["object"] = map[string]interface{}{"objectType": "activity", "id": data["messageId"]}
+	case "typing":
+		delete(data, "verb")
+		data["eventType"] = "status.start_typing"
+		if k.Event == "stopped" {
+			data["eventType"] = "status.stop_typing"
+		}
+	case "presence":
+		delete(data, "verb")
+		delete(data, "roomId")
+		data["eventType"] = "apheleia.subscription_update"
+		data["personId"] = fmt.Sprintf("loadgen-person-%d", seq%100)
+		data["status"] = "active"
+	case "meetings":
+		delete(data, "verb")
+		delete(data, "roomId")
+		data["eventType"] = "locus.difference"
+		data["locusUrl"] = fmt.Sprintf("https://locus.loadgen.local/loci/%d", seq%10)
+		data["meetingState"] = "ACTIVE"
+		if k.Event == "ended" {
+			data["meetingState"] = "INACTIVE"
+		}
+	case "calls":
+		delete(data, "verb")
+		delete(data, "roomId")
+		data["callId"] = fmt.Sprintf("loadgen-call-%d", seq%10)
+		data["correlationId"] = fmt.Sprintf("loadgen-correlation-%d", seq%10)
+		data["direction"] = "inbound"
+		data["callState"] = map[string]string{"incoming": "alerting", "answered": "connected", "disconnected": "disconnected"}[k.Event]
+		data["callerId"] = "sip:loadgen@hookbuster.local"
+	case config.ResourceUnclassified:
+		data["verb"] = "tag"
+		data["object"] = map[string]interface{}{"objectType": "tag"}
+	}
+
+	return config.WebhookEvent{
+		Resource:  k.Resource,
+		Event:     k.Event,
+		Data:      data,
+		Timestamp: now.UnixMilli(),
+	}
+}
+
+// Sink is one destination under load, typically a pipeline's targets.
+type Sink struct {
+	Name string
+
+	// Accepts filters the kinds this sink subscribes to; nil accepts all.
+	Accepts func(Kind) bool
+
+	// Send delivers one event and returns once it is acknowledged.
+	Send func(config.WebhookEvent) error
+}
+
+// Runner generates events at Rate and sends each one to every accepting
+// sink, using up to Concurrency sends in flight per sink.
+type Runner struct {
+	// Rate is events generated per second; 0 generates as fast as the
+	// sinks accept them.
+	Rate float64
+
+	// Duration and Count bound the run; whichever is reached first ends it.
+	// Zero means unbounded, so at least one should be set.
+	Duration time.Duration
+	Count    int
+
+	// Concurrency is the number of sends in flight per sink (default 1).
+	Concurrency int
+
+	Mix   *Mix
+	Sinks []Sink
+
+	// Seed fixes the kind sequence for reproducible runs; 0 uses the clock.
+	Seed int64
+}
+
+// Run generates load until the run is bounded or stop is closed, then waits
+// for in-flight sends and returns one report per sink.
+func (r *Runner) Run(stop <-chan struct{}) []Report {
+	workers := r.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	mix := r.Mix
+	if mix == nil {
+		mix = UniformMix()
+	}
+	seed := r.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	type job struct {
+		event config.WebhookEvent
+	}
+	queues := make([]chan job, len(r.Sinks))
+	stats := make([]*Stats, len(r.Sinks))
+	var wg sync.WaitGroup
+	for i, sink := range r.Sinks {
+		queues[i] = make(chan job, workers)
+		stats[i] = NewStats()
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(send func(config.WebhookEvent) error, q <-chan job, st *Stats) {
+				defer wg.Done()
+				for j := range q {
+					start := time.Now()
+					err := send(j.event)
+					st.Record(time.Since(start), err)
+				}
+			}(sink.Send, queues[i], stats[i])
+		}
+	}
+
+	var deadline <-chan time.Time
+	if r.Duration > 0 {
+		timer := time.NewTimer(r.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	var interval time.Duration
+	if r.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / r.Rate)
+	}
+
+	started := time.Now()
+	next := started
+generate:
+	for seq := int64(1); r.Count <= 0 || seq <= int64(r.Count); seq++ {
+		if interval > 0 {
+			// Pace against the schedule rather than the previous send so
+			// a slow iteration does not lower the average rate.
+			next = next.Add(interval)
+			if wait := time.Until(next); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-deadline:
+					break generate
+				case <-stop:
+					break generate
+				}
+			}
+		}
+		select {
+		case <-deadline:
+			break generate
+		case <-stop:
+			break generate
+		default:
+		}
+
+		kind := mix.Pick(rng)
+		event := Synthesize(kind, seq, time.Now())
+		for i, sink := range r.Sinks {
+			if sink.Accepts != nil && !sink.Accepts(kind) {
+				continue
+			}
+			// Blocks when the sink's workers are saturated, which caps
+			// the achieved rate at what the sink can absorb.
+			select {
+			case queues[i] <- job{event: event}:
+			case <-deadline:
+				break generate
+			case <-stop:
+				break generate
+			}
+		}
+	}
+
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	reports := make([]Report, len(r.Sinks))
+	for i, sink := range r.Sinks {
+		reports[i] = stats[i].Report(sink.Name, elapsed)
+	}
+	return reports
+}
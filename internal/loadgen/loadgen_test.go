@@ -0,0 +1,241 @@
+package loadgen
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+func TestAllKinds(t *testing.T) {
+	kinds := AllKinds()
+	seen := make(map[string]bool)
+	for _, k := range kinds {
+		if k.Event == "all" {
+			t.Errorf("AllKinds() should not include %s", k)
+		}
+		seen[k.String()] = true
+	}
+	for _, want := range []string{"messages:created", "memberships:deleted", "rooms:updated", "attachmentActions:created"} {
+		if !seen[want] {
+			t.Errorf("AllKinds() missing %s", want)
+		}
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	m, err := ParseMix("messages:created=3, memberships=0, teams=2")
+	if err != nil {
+		t.Fatalf("ParseMix() error: %v", err)
+	}
+
+	var got []string
+	for _, k := range m.Kinds() {
+		got = append(got, k.String())
+	}
+	// Zero-weight entries are dropped; a bare resource expands to its events
+	want := "messages:created,teams:created,teams:updated"
+	if strings.Join(got, ",") != want {
+		t.Errorf("kinds = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	counts := make(map[string]int)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		counts[m.Pick(r).String()]++
+	}
+	// messages:created has weight 3 of 5
+	if n := counts["messages:created"]; n < 5700 || n > 6300 {
+		t.Errorf("messages:created picked %d/10000 times, want about 6000", n)
+	}
+}
+
+func TestParseMixErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"messages", "kind=weight"},
+		{"widgets=1", "unknown resource"},
+		{"rooms:deleted=1", "no event"},
+		{"messages=-1", "non-negative"},
+		{"messages=0", "no positive weights"},
+	}
+	for _, tt := range tests {
+		_, err := ParseMix(tt.spec)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseMix(%q) error = %v, want it to contain %q", tt.spec, err, tt.want)
+		}
+	}
+
+	if m, err := ParseMix(""); err != nil || len(m.Kinds()) != len(AllKinds()) {
+		t.Errorf("ParseMix(\"\") should be the uniform mix")
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	now := time.Date(2026, 2, 7, 2, 8, 14, 0, time.UTC)
+	event := Synthesize(Kind{Resource: "messages", Event: "created"}, 42, now)
+
+	if event.Resource != "messages" || event.Event != "created" || event.Timestamp != now.UnixMilli() {
+		t.Errorf("event = %+v", event)
+	}
+	data := event.Data.(map[string]interface{})
+	if data["verb"] != "post" {
+		t.Errorf("verb = %v, want post", data["verb"])
+	}
+	if data["id"] != "loadgen-42" || data["content"] == nil || data["roomId"] == nil {
+		t.Errorf("data = %v", data)
+	}
+
+	card := Synthesize(Kind{Resource: "attachmentActions", Event: "created"}, 1, now).Data.(map[string]interface{})
+	if card["verb"] != "cardAction" || card["parentId"] == nil {
+		t.Errorf("card data = %v", card)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+		{0, 1 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if Percentile(nil, 50) != 0 {
+		t.Error("Percentile of no samples should be 0")
+	}
+}
+
+func TestRunner_CountsAndFilters(t *testing.T) {
+	var mu sync.Mutex
+	var roomsSeen int
+	sendErr := errors.New("receiver said no")
+
+	r := &Runner{
+		Count:       50,
+		Concurrency: 4,
+		Seed:        7,
+		Sinks: []Sink{
+			{
+				Name: "all",
+				Send: func(e config.WebhookEvent) error {
+					if e.Resource == "messages" {
+						return sendErr
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "rooms-only",
+				Accepts: func(k Kind) bool { return k.Resource == "rooms" },
+				Send: func(e config.WebhookEvent) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if e.Resource != "rooms" {
+						t.Errorf("rooms-only sink got %s:%s", e.Resource, e.Event)
+					}
+					roomsSeen++
+					return nil
+				},
+			},
+		},
+	}
+	reports := r.Run(nil)
+
+	all := reports[0]
+	if all.Sent != 50 {
+		t.Errorf("all.Sent = %d, want 50", all.Sent)
+	}
+	if all.Failed == 0 || all.Failed == 50 {
+		t.Errorf("all.Failed = %d, want some but not all", all.Failed)
+	}
+	if len(all.Errors) != 1 || all.Errors[0].Message != sendErr.Error() || all.Errors[0].Count != all.Failed {
+		t.Errorf("all.Errors = %+v", all.Errors)
+	}
+	if reports[1].Sent != roomsSeen || roomsSeen == 0 || roomsSeen == 50 {
+		t.Errorf("rooms-only sent %d (saw %d), want a subset of 50", reports[1].Sent, roomsSeen)
+	}
+}
+
+func TestRunner_PacesToRate(t *testing.T) {
+	r := &Runner{
+		Rate:  200,
+		Count: 20,
+		Sinks: []Sink{{Name: "s", Send: func(config.WebhookEvent) error { return nil }}},
+	}
+	start := time.Now()
+	reports := r.Run(nil)
+	elapsed := time.Since(start)
+
+	if reports[0].Sent != 20 {
+		t.Errorf("Sent = %d, want 20", reports[0].Sent)
+	}
+	// 20 events at 200/s are scheduled over 100ms
+	if elapsed < 90*time.Millisecond {
+		t.Errorf("run took %s, want at least ~100ms at 200 events/s", elapsed)
+	}
+}
+
+func TestRunner_StopsOnDuration(t *testing.T) {
+	r := &Runner{
+		Rate:     1000,
+		Duration: 50 * time.Millisecond,
+		Sinks:    []Sink{{Name: "s", Send: func(config.WebhookEvent) error { return nil }}},
+	}
+	done := make(chan []Report)
+	go func() { done <- r.Run(nil) }()
+
+	select {
+	case reports := <-done:
+		if reports[0].Sent == 0 {
+			t.Error("no events sent before the duration elapsed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runner did not stop after its duration")
+	}
+}
+
+func TestRunner_StopsOnDurationWhileSinkBlocked(t *testing.T) {
+	release := make(chan struct{})
+	r := &Runner{
+		Duration:    50 * time.Millisecond,
+		Concurrency: 1,
+		Sinks: []Sink{{Name: "s", Send: func(config.WebhookEvent) error {
+			<-release
+			return nil
+		}}},
+	}
+	done := make(chan []Report)
+	go func() { done <- r.Run(nil) }()
+
+	// Let the duration elapse while the generator waits on the full queue
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+
+	select {
+	case reports := <-done:
+		// One send in flight and one queued; nothing enqueued after the deadline
+		if reports[0].Sent != 2 {
+			t.Errorf("Sent = %d, want 2", reports[0].Sent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runner did not stop after its duration")
+	}
+}
@@ -0,0 +1,129 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package loadgen
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxErrorLength truncates error messages so errors that differ only in a
+// trailing detail are grouped together.
+const maxErrorLength = 120
+
+// Stats accumulates send latencies and errors. It is safe for concurrent use.
+type Stats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	failed    int
+	errors    map[string]int
+}
+
+// NewStats creates an empty Stats.
+func NewStats() *Stats {
+	return &Stats{errors: make(map[string]int)}
+}
+
+// Record adds one send with its latency and result.
+func (s *Stats) Record(latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.failed++
+		msg := err.Error()
+		if len(msg) > maxErrorLength {
+			msg = msg[:maxErrorLength] + "…"
+		}
+		s.errors[msg]++
+	}
+}
+
+// ErrorCount is one distinct error and how often it occurred.
+type ErrorCount struct {
+	Message string
+	Count   int
+}
+
+// Report summarises a sink's run.
+type Report struct {
+	Name    string
+	Sent    int // total sends, including failures
+	Failed  int
+	Elapsed time.Duration
+
+	// Throughput is successful sends per second.
+	Throughput float64
+
+	P50, P90, P99, Max time.Duration
+
+	// Errors lists distinct errors, most frequent first.
+	Errors []ErrorCount
+}
+
+// ErrorRate is the fraction of sends that failed.
+func (r Report) ErrorRate() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Sent)
+}
+
+// Report computes the summary for the sends recorded so far.
+func (s *Stats) Report(name string, elapsed time.Duration) Report {
+	s.mu.Lock()
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	failed := s.failed
+	errs := make([]ErrorCount, 0, len(s.errors))
+	for msg, n := range s.errors {
+		errs = append(errs, ErrorCount{Message: msg, Count: n})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Count != errs[j].Count {
+			return errs[i].Count > errs[j].Count
+		}
+		return errs[i].Message < errs[j].Message
+	})
+
+	r := Report{
+		Name:    name,
+		Sent:    len(sorted),
+		Failed:  failed,
+		Elapsed: elapsed,
+		P50:     Percentile(sorted, 50),
+		P90:     Percentile(sorted, 90),
+		P99:     Percentile(sorted, 99),
+		Errors:  errs,
+	}
+	if len(sorted) > 0 {
+		r.Max = sorted[len(sorted)-1]
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(r.Sent-r.Failed) / elapsed.Seconds()
+	}
+	return r
+}
+
+// Percentile returns the p-th percentile (0–100) of sorted latencies using
+// the nearest-rank method, or 0 for an empty slice.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,197 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package metadata caches room and person details fetched from the Webex
+// REST API, so events can be enriched without one API call per event. What
+// a room looks like depends on who is asking (a direct room is titled
+// after the other member), so each pipeline keeps its own Cache, filled
+// with its own token.
+package metadata
+
+import (
+	"sync"
+	"time"
+
+	webex "github.com/WebexCommunity/webex-go-sdk/v2"
+	"github.com/WebexCommunity/webex-go-sdk/v2/people"
+	"github.com/WebexCommunity/webex-go-sdk/v2/rooms"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// Room holds the cached details of a room. TeamID is a UUID, like the
+// other identifiers in forwarded events.
+type Room struct {
+	Title  string
+	Type   string // "direct" or "group"
+	TeamID string
+}
+
+// IsDirect reports whether the room is a 1:1 space.
+func (r Room) IsDirect() bool {
+	return r.Type == "direct"
+}
+
+// Person holds the cached details of a person.
+type Person struct {
+	DisplayName string
+	Emails      []string
+	OrgID       string
+}
+
+// Source fetches rooms and people by REST ID.
+type Source interface {
+	Room(id string) (*rooms.Room, error)
+	Person(id string) (*people.Person, error)
+}
+
+// sdkSource is the Source backed by the Webex rooms and people APIs.
+type sdkSource struct {
+	client *webex.WebexClient
+}
+
+// NewSDKSource returns a Source that uses client's rooms and people APIs.
+func NewSDKSource(client *webex.WebexClient) Source {
+	return sdkSource{client: client}
+}
+
+func (s sdkSource) Room(id string) (*rooms.Room, error) {
+	return s.client.Rooms().Get(id)
+}
+
+func (s sdkSource) Person(id string) (*people.Person, error) {
+	return s.client.People().Get(id)
+}
+
+// entry is a cached value and the time it goes stale.
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// Cache is a TTL-bounded cache of rooms and people, keyed by UUID so REST
+// IDs and UUIDs of the same resource share an entry. It is safe for
+// concurrent use, but must only be filled through one token's Source.
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu     sync.Mutex
+	rooms  map[string]entry[Room]
+	people map[string]entry[Person]
+}
+
+// New returns an empty cache tuned by opts, which may be nil for the
+// defaults. opts is assumed to be validated.
+func New(opts *config.CacheOptions) *Cache {
+	ttl, _ := time.ParseDuration(config.DefaultCacheTTL)
+	maxEntries := config.DefaultCacheMaxEntries
+	if opts != nil {
+		if d, err := time.ParseDuration(opts.TTL); err == nil && d > 0 {
+			ttl = d
+		}
+		if opts.MaxEntries > 0 {
+			maxEntries = opts.MaxEntries
+		}
+	}
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		rooms:      make(map[string]entry[Room]),
+		people:     make(map[string]entry[Person]),
+	}
+}
+
+// Room returns the details of the room with the given ID, fetching them
+// from src if they are not cached or stale. cluster is the room's Hydra
+// cluster, used to build its REST ID.
+func (c *Cache) Room(src Source, cluster, id string) (Room, error) {
+	return get(c, c.rooms, hydra.UUID(id), func() (Room, error) {
+		r, err := src.Room(hydra.IDIn(cluster, hydra.Room, id))
+		if err != nil {
+			return Room{}, err
+		}
+		room := Room{Title: r.Title, Type: r.Type}
+		if r.TeamID != "" {
+			room.TeamID = hydra.UUID(r.TeamID)
+		}
+		return room, nil
+	})
+}
+
+// Person returns the details of the person with the given ID, fetching
+// them from src if they are not cached or stale.
+func (c *Cache) Person(src Source, id string) (Person, error) {
+	return get(c, c.people, hydra.UUID(id), func() (Person, error) {
+		p, err := src.Person(hydra.ID(hydra.People, id))
+		if err != nil {
+			return Person{}, err
+		}
+		return Person{DisplayName: p.DisplayName, Emails: p.Emails, OrgID: hydra.UUID(p.OrgID)}, nil
+	})
+}
+
+// InvalidateRoom drops the cached details of a room.
+func (c *Cache) InvalidateRoom(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.rooms, hydra.UUID(id))
+}
+
+// InvalidatePerson drops the cached details of a person.
+func (c *Cache) InvalidatePerson(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.people, hydra.UUID(id))
+}
+
+// get returns the fresh entry for key in m, or fetches and stores it.
+// Fetch errors are not cached. The lock is not held while fetching, so
+// concurrent misses for one key may fetch it more than once.
+func get[T any](c *Cache, m map[string]entry[T], key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	e, ok := m[key]
+	now := c.now()
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := m[key]; !ok && len(m) >= c.maxEntries {
+		evict(m, c.now())
+	}
+	m[key] = entry[T]{value: value, expires: c.now().Add(c.ttl)}
+	return value, nil
+}
+
+// evict makes room for one entry in m: it drops every stale entry, or
+// else the one closest to going stale.
+func evict[T any](m map[string]entry[T], now time.Time) {
+	var oldest string
+	var oldestExpiry time.Time
+	removed := false
+	for k, e := range m {
+		if !now.Before(e.expires) {
+			delete(m, k)
+			removed = true
+			continue
+		}
+		if oldest == "" || e.expires.Before(oldestExpiry) {
+			oldest, oldestExpiry = k, e.expires
+		}
+	}
+	if !removed && oldest != "" {
+		delete(m, oldest)
+	}
+}
@@ -0,0 +1,179 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WebexCommunity/webex-go-sdk/v2/people"
+	"github.com/WebexCommunity/webex-go-sdk/v2/rooms"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/hydra"
+)
+
+// fakeSource serves rooms and people from memory and counts lookups.
+type fakeSource struct {
+	rooms  map[string]*rooms.Room
+	people map[string]*people.Person
+	calls  int
+	lastID string
+}
+
+func (s *fakeSource) Room(id string) (*rooms.Room, error) {
+	s.calls++
+	s.lastID = id
+	if r, ok := s.rooms[hydra.UUID(id)]; ok {
+		return r, nil
+	}
+	return nil, errors.New("404 not found")
+}
+
+func (s *fakeSource) Person(id string) (*people.Person, error) {
+	s.calls++
+	s.lastID = id
+	if p, ok := s.people[hydra.UUID(id)]; ok {
+		return p, nil
+	}
+	return nil, errors.New("404 not found")
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		rooms: map[string]*rooms.Room{
+			"r1": {Title: "Planning", Type: "group", TeamID: hydra.ID(hydra.Team, "t1")},
+			"r2": {Title: "Bob", Type: "direct"},
+			"r3": {Title: "Ops", Type: "group"},
+		},
+		people: map[string]*people.Person{
+			"p1": {DisplayName: "Alice", Emails: []string{"alice@example.com"}, OrgID: hydra.ID(hydra.Organization, "o1")},
+		},
+	}
+}
+
+// newTestCache returns a cache whose clock is advanced through *now.
+func newTestCache(opts *config.CacheOptions) (*Cache, *time.Time) {
+	c := New(opts)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+// ── Cache tests ─────────────────────────────────────────────────────────
+
+func TestNew_Defaults(t *testing.T) {
+	c := New(nil)
+	if c.ttl != 10*time.Minute || c.maxEntries != config.DefaultCacheMaxEntries {
+		t.Errorf("New(nil) = ttl %v, max %d", c.ttl, c.maxEntries)
+	}
+	c = New(&config.CacheOptions{TTL: "30s", MaxEntries: 5})
+	if c.ttl != 30*time.Second || c.maxEntries != 5 {
+		t.Errorf("New() = ttl %v, max %d, want 30s, 5", c.ttl, c.maxEntries)
+	}
+}
+
+func TestRoom(t *testing.T) {
+	src := newFakeSource()
+	c, _ := newTestCache(nil)
+
+	room, err := c.Room(src, "urn:TEAM:eu-central-1_k", "r1")
+	if err != nil {
+		t.Fatalf("Room() error: %v", err)
+	}
+	if want := (Room{Title: "Planning", Type: "group", TeamID: "t1"}); room != want {
+		t.Errorf("Room() = %+v, want %+v", room, want)
+	}
+	if room.IsDirect() {
+		t.Error("IsDirect() = true for a group room")
+	}
+	if want := hydra.IDIn("urn:TEAM:eu-central-1_k", hydra.Room, "r1"); src.lastID != want {
+		t.Errorf("looked up %q, want %q", src.lastID, want)
+	}
+
+	// The Hydra ID of the same room is served from the cache.
+	if _, err := c.Room(src, hydra.DefaultCluster, hydra.ID(hydra.Room, "r1")); err != nil || src.calls != 1 {
+		t.Errorf("second Room() = %v after %d lookups, want a cache hit", err, src.calls)
+	}
+}
+
+func TestPerson(t *testing.T) {
+	src := newFakeSource()
+	c, _ := newTestCache(nil)
+
+	person, err := c.Person(src, "p1")
+	if err != nil {
+		t.Fatalf("Person() error: %v", err)
+	}
+	if person.DisplayName != "Alice" || person.OrgID != "o1" || len(person.Emails) != 1 {
+		t.Errorf("Person() = %+v", person)
+	}
+	if src.lastID != hydra.ID(hydra.People, "p1") {
+		t.Errorf("looked up %q, want the Hydra ID", src.lastID)
+	}
+	c.Person(src, "p1")
+	if src.calls != 1 {
+		t.Errorf("lookups = %d, want 1", src.calls)
+	}
+}
+
+func TestExpiryAndInvalidation(t *testing.T) {
+	src := newFakeSource()
+	c, now := newTestCache(&config.CacheOptions{TTL: "1m"})
+
+	c.Room(src, hydra.DefaultCluster, "r1")
+	*now = now.Add(59 * time.Second)
+	c.Room(src, hydra.DefaultCluster, "r1")
+	if src.calls != 1 {
+		t.Fatalf("lookups = %d before expiry, want 1", src.calls)
+	}
+	*now = now.Add(time.Second)
+	c.Room(src, hydra.DefaultCluster, "r1")
+	if src.calls != 2 {
+		t.Fatalf("lookups = %d after expiry, want 2", src.calls)
+	}
+
+	src.rooms["r1"] = &rooms.Room{Title: "Renamed", Type: "group"}
+	c.InvalidateRoom(hydra.ID(hydra.Room, "r1"))
+	if room, _ := c.Room(src, hydra.DefaultCluster, "r1"); room.Title != "Renamed" || src.calls != 3 {
+		t.Errorf("Room() after invalidation = %+v after %d lookups", room, src.calls)
+	}
+
+	c.Person(src, "p1")
+	c.InvalidatePerson("p1")
+	c.Person(src, "p1")
+	if src.calls != 5 {
+		t.Errorf("lookups = %d after person invalidation, want 5", src.calls)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	src := newFakeSource()
+	c, _ := newTestCache(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Room(src, hydra.DefaultCluster, "missing"); err == nil {
+			t.Fatal("Room() error = nil, want 404")
+		}
+	}
+	if src.calls != 2 {
+		t.Errorf("lookups = %d, want 2", src.calls)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	src := newFakeSource()
+	c, now := newTestCache(&config.CacheOptions{MaxEntries: 2})
+
+	c.Room(src, hydra.DefaultCluster, "r1")
+	*now = now.Add(time.Second)
+	c.Room(src, hydra.DefaultCluster, "r2")
+	*now = now.Add(time.Second)
+	c.Room(src, hydra.DefaultCluster, "r3")
+
+	if len(c.rooms) != 2 {
+		t.Fatalf("cached rooms = %d, want 2", len(c.rooms))
+	}
+	if _, ok := c.rooms["r1"]; ok {
+		t.Error("r1 is still cached, want the oldest entry evicted")
+	}
+}
@@ -0,0 +1,215 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package mockmercury
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Actor is the person performing an injected activity.
+type Actor struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	Email       string `json:"emailAddress,omitempty"`
+	OrgID       string `json:"orgId,omitempty"`
+}
+
+// DefaultActor performs activities that do not name an actor.
+var DefaultActor = Actor{
+	ID:          "mock-user",
+	DisplayName: "Mock User",
+	Email:       "user@mock.local",
+	OrgID:       "mock-org",
+}
+
+// Activity describes a conversation activity to inject. Only Verb is
+// required; the Mercury object is built from the other fields unless Object
+// is set explicitly.
+type Activity struct {
+	ID     string `json:"id,omitempty"`
+	Verb   string `json:"verb"`
+	RoomID string `json:"roomId,omitempty"`
+	Actor  *Actor `json:"actor,omitempty"`
+
+	// Text is the message text (post, share) or room title (create, update).
+	Text string `json:"text,omitempty"`
+	// MessageID is the message deleted (delete) or the card message
+	// submitted (cardAction).
+	MessageID string `json:"messageId,omitempty"`
+	// PersonID is the member added, removed or (un)assigned moderator.
+	PersonID string `json:"personId,omitempty"`
+	// ParentID makes a post a thread reply.
+	ParentID string `json:"parentId,omitempty"`
+	// Inputs are the submitted card inputs (cardAction).
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+
+	// Object overrides the generated Mercury object.
+	Object map[string]interface{} `json:"object,omitempty"`
+}
+
+// Post returns a new message activity.
+func Post(roomID, text string) Activity {
+	return Activity{Verb: "post", RoomID: roomID, Text: text}
+}
+
+// Share returns a new message-with-files activity.
+func Share(roomID, text string) Activity {
+	return Activity{Verb: "share", RoomID: roomID, Text: text}
+}
+
+// Delete returns a message deletion activity.
+func Delete(roomID, messageID string) Activity {
+	return Activity{Verb: "delete", RoomID: roomID, MessageID: messageID}
+}
+
+// Add returns a membership created activity.
+func Add(roomID, personID string) Activity {
+	return Activity{Verb: "add", RoomID: roomID, PersonID: personID}
+}
+
+// Leave returns a membership deleted activity for a member leaving.
+func Leave(roomID, personID string) Activity {
+	return Activity{Verb: "leave", RoomID: roomID, PersonID: personID}
+}
+
+// CardAction returns an adaptive card submission activity.
+func CardAction(roomID, messageID string, inputs map[string]interface{}) Activity {
+	return Activity{Verb: "cardAction", RoomID: roomID, MessageID: messageID, Inputs: inputs}
+}
+
+// Validate reports whether the activity can be injected.
+func (a Activity) Validate() error {
+	if a.Verb == "" {
+		return fmt.Errorf("activity verb is required")
+	}
+	return nil
+}
+
+// wire renders the activity in the shape Mercury delivers it.
+func (a Activity) wire() map[string]interface{} {
+	actor := DefaultActor
+	if a.Actor != nil {
+		actor = *a.Actor
+	}
+
+	act := map[string]interface{}{
+		"id":         a.ID,
+		"objectType": "activity",
+		"verb":       a.Verb,
+		"published":  time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		"actor": map[string]interface{}{
+			"id":           actor.ID,
+			"objectType":   "person",
+			"displayName":  actor.DisplayName,
+			"emailAddress": actor.Email,
+			"orgId":        actor.OrgID,
+		},
+		"object": a.object(),
+	}
+	if a.RoomID != "" {
+		act["target"] = map[string]interface{}{
+			"id":         a.RoomID,
+			"objectType": "conversation",
+		}
+	}
+	switch {
+	case a.ParentID != "":
+		act["parent"] = map[string]interface{}{"id": a.ParentID, "type": "reply"}
+	case a.Verb == "cardAction" && a.MessageID != "":
+		act["parent"] = map[string]interface{}{"id": a.MessageID, "type": "cardAction"}
+	}
+	return act
+}
+
+// object builds the Mercury object for the verb.
+func (a Activity) object() map[string]interface{} {
+	if a.Object != nil {
+		return a.Object
+	}
+	switch a.Verb {
+	case "post":
+		return map[string]interface{}{"objectType": "comment", "displayName": a.Text}
+	case "share":
+		return map[string]interface{}{"objectType": "content", "displayName": a.Text, "contentCategory": "documents"}
+	case "delete":
+		return map[string]interface{}{"objectType": "activity", "id": a.MessageID}
+	case "create", "update":
+		return map[string]interface{}{"objectType": "conversation", "id": a.RoomID, "displayName": a.Text}
+	case "add", "leave", "remove", "assignModerator", "unassignModerator":
+		return map[string]interface{}{"objectType": "person", "id": a.PersonID}
+	case "cardAction":
+		return map[string]interface{}{"objectType": "submit", "inputs": a.Inputs}
+	}
+	return map[string]interface{}{}
+}
+
+// Step is one line of a script: an activity and how long to wait before
+// injecting it.
+type Step struct {
+	// Delay is a Go duration ("500ms", "2s") waited before this step.
+	Delay string `json:"delay,omitempty"`
+	Activity
+
+	delay time.Duration
+}
+
+// ReadScript parses a script, one JSON Step per line. Blank lines and lines
+// starting with # are skipped.
+func ReadScript(r io.Reader) ([]Step, error) {
+	var steps []Step
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var step Step
+		if err := json.Unmarshal([]byte(text), &step); err != nil {
+			return nil, fmt.Errorf("script line %d: %w", line, err)
+		}
+		if err := step.Validate(); err != nil {
+			return nil, fmt.Errorf("script line %d: %w", line, err)
+		}
+		if step.Delay != "" {
+			d, err := time.ParseDuration(step.Delay)
+			if err != nil {
+				return nil, fmt.Errorf("script line %d: invalid delay %q: %w", line, step.Delay, err)
+			}
+			step.delay = d
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return steps, nil
+}
+
+// Play injects steps in order, honouring their delays, until done or stop
+// is closed. It returns the first injection error.
+func (s *Server) Play(steps []Step, stop <-chan struct{}) error {
+	for i, step := range steps {
+		if step.delay > 0 {
+			select {
+			case <-time.After(step.delay):
+			case <-stop:
+				return nil
+			}
+		}
+		if err := s.Inject(step.Activity); err != nil {
+			return fmt.Errorf("script step %d (%s): %w", i+1, step.Verb, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package mockmercury emulates the Webex endpoints a Listener talks to —
+// device registration (WDM), the Mercury WebSocket and people/me — so
+// filtering, forwarding and reconnection can be exercised end to end with
+// no network. Activities are injected from Go, from a script file or over
+// HTTP, and are delivered unencrypted so no KMS is needed.
+package mockmercury
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Paths served by the mock. APIPath and DevicesPath are what the listener's
+// endpoint overrides should point at.
+const (
+	APIPath     = "/v1"
+	DevicesPath = "/wdm/api/v1/devices"
+	MercuryPath = "/mercury/device"
+
+	// InjectPath accepts a JSON Activity (POST) and delivers it to every
+	// connected client. DropPath (POST) closes every Mercury connection.
+	InjectPath = "/mock/activities"
+	DropPath   = "/mock/drop"
+)
+
+// ErrNoClients is returned by Inject when no Mercury client is connected.
+var ErrNoClients = errors.New("mockmercury: no Mercury clients connected")
+
+// Person is the identity returned by people/me and used as the registered
+// device's user.
+type Person struct {
+	ID          string   `json:"id"`
+	Emails      []string `json:"emails"`
+	DisplayName string   `json:"displayName"`
+	OrgID       string   `json:"orgId,omitempty"`
+	Type        string   `json:"type,omitempty"`
+}
+
+// Options configures a Server. The zero value accepts any token.
+type Options struct {
+	// Token, if set, is the only access token the mock accepts.
+	Token string
+
+	// Me is returned by people/me. Defaults to DefaultMe.
+	Me *Person
+}
+
+// DefaultMe is the identity the mock reports when Options.Me is nil.
+var DefaultMe = Person{
+	ID:          "mock-me",
+	Emails:      []string{"hookbuster@mock.local"},
+	DisplayName: "Hookbuster Mock",
+	OrgID:       "mock-org",
+	Type:        "bot",
+}
+
+// Server is a mock of the Webex device, Mercury and people endpoints.
+type Server struct {
+	token    string
+	me       Person
+	upgrader websocket.Upgrader
+	mux      *http.ServeMux
+	httpSrv  *http.Server
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[*mercuryConn]struct{}
+	// connected is closed and replaced whenever a client completes the
+	// Mercury handshake, waking WaitForConnections.
+	connected chan struct{}
+
+	registrations atomic.Int64
+	seq           atomic.Int64
+}
+
+// mercuryConn is one authenticated Mercury WebSocket.
+type mercuryConn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (c *mercuryConn) writeJSON(v interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return c.ws.WriteJSON(v)
+}
+
+// New creates a Server. Call Start to listen on an address, or mount
+// Handler on an httptest.Server.
+func New(opts Options) *Server {
+	s := &Server{
+		token:     opts.Token,
+		me:        DefaultMe,
+		conns:     make(map[*mercuryConn]struct{}),
+		connected: make(chan struct{}),
+	}
+	if opts.Me != nil {
+		s.me = *opts.Me
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc(DevicesPath, s.handleDevices)
+	s.mux.HandleFunc(DevicesPath+"/", s.handleDevices)
+	s.mux.HandleFunc(APIPath+"/people/me", s.handleMe)
+	s.mux.HandleFunc(APIPath+"/messages/", s.handleMessage)
+	s.mux.HandleFunc(MercuryPath, s.handleMercury)
+	s.mux.HandleFunc(InjectPath, s.handleInject)
+	s.mux.HandleFunc(DropPath, s.handleDrop)
+	s.httpSrv = &http.Server{Handler: s.mux, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Handler returns the HTTP handler serving all mock endpoints.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start listens on addr (e.g. "127.0.0.1:0") and serves in the background.
+func (s *Server) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mockmercury: failed to listen on %s: %w", addr, err)
+	}
+	s.listener = ln
+	go func() { _ = s.httpSrv.Serve(ln) }()
+	return nil
+}
+
+// URL returns the base URL of a started server, e.g. http://127.0.0.1:8765.
+func (s *Server) URL() string {
+	if s.listener == nil {
+		return ""
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// Close drops every Mercury connection and stops the server.
+func (s *Server) Close() error {
+	s.DropConnections()
+	return s.httpSrv.Close()
+}
+
+// Registrations returns how many device registrations the mock has served.
+func (s *Server) Registrations() int {
+	return int(s.registrations.Load())
+}
+
+// Connections returns the number of authenticated Mercury clients.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// WaitForConnections blocks until at least n Mercury clients are connected
+// or the timeout expires.
+func (s *Server) WaitForConnections(n int, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		count, wake := len(s.conns), s.connected
+		s.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-wake:
+		case <-deadline:
+			return fmt.Errorf("mockmercury: %d client(s) connected after %s, want %d", count, timeout, n)
+		}
+	}
+}
+
+// DropConnections closes every Mercury WebSocket without a close frame, as
+// a network failure would, so clients exercise their reconnect path.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[*mercuryConn]struct{})
+	s.mu.Unlock()
+
+	for c := range conns {
+		_ = c.ws.Close()
+	}
+}
+
+// Inject delivers a conversation.activity event for a to every connected
+// client.
+func (s *Server) Inject(a Activity) error {
+	msg := s.envelope(a)
+
+	s.mu.Lock()
+	conns := make([]*mercuryConn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	if len(conns) == 0 {
+		return ErrNoClients
+	}
+	var errs []error
+	for _, c := range conns {
+		if err := c.writeJSON(msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// envelope wraps an activity in the Mercury event framing.
+func (s *Server) envelope(a Activity) map[string]interface{} {
+	n := s.seq.Add(1)
+	if a.ID == "" {
+		a.ID = fmt.Sprintf("mock-activity-%d", n)
+	}
+	return map[string]interface{}{
+		"id":             fmt.Sprintf("mock-event-%d", n),
+		"timestamp":      time.Now().UnixMilli(),
+		"sequenceNumber": n,
+		"data": map[string]interface{}{
+			"eventType": "conversation.activity",
+			"activity":  a.wire(),
+		},
+	}
+}
+
+// authorized checks the bearer token on REST requests.
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.validToken(token)
+}
+
+func (s *Server) validToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	return s.token == "" || token == s.token
+}
+
+// handleDevices emulates WDM device registration. The returned Mercury URL
+// points back at this server on whatever host the client used.
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	n := s.registrations.Add(1)
+	deviceURL := fmt.Sprintf("http://%s%s/mock-device-%d", r.Host, DevicesPath, n)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"url":          deviceURL,
+		"webSocketUrl": "ws://" + r.Host + MercuryPath,
+		"userId":       s.me.ID,
+		"deviceType":   "TEAMS_SDK_JS",
+	})
+}
+
+// handleMe serves people/me.
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.me)
+}
+
+// handleMessage answers message lookups, made for card actions and
+// attachments, with a message that has no files or cards.
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id": strings.TrimPrefix(r.URL.Path, APIPath+"/messages/")})
+}
+
+// handleMercury upgrades to a WebSocket and runs the Mercury handshake: the
+// client sends an authorization message and waits for a buffer state event
+// before it considers itself connected.
+func (s *Server) handleMercury(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &mercuryConn{ws: ws}
+
+	_ = ws.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var auth struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := ws.ReadJSON(&auth); err != nil || auth.Type != "authorization" || !s.validToken(auth.Data.Token) {
+		_ = c.writeJSON(map[string]interface{}{"id": auth.ID, "type": "error", "data": map[string]interface{}{"reason": "unauthorized"}})
+		_ = ws.Close()
+		return
+	}
+	_ = ws.SetReadDeadline(time.Time{})
+
+	if err := c.writeJSON(map[string]interface{}{
+		"id": auth.ID,
+		"data": map[string]interface{}{
+			"eventType":    "mercury.buffer_state",
+			"conversation": "UNKNOWN",
+		},
+	}); err != nil {
+		_ = ws.Close()
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	close(s.connected)
+	s.connected = make(chan struct{})
+	s.mu.Unlock()
+
+	// Drain client frames (pings, acks) until the connection goes away.
+	// Control frames are answered by the default handlers.
+	for {
+		if _, _, err := ws.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	_ = ws.Close()
+}
+
+// handleInject delivers the posted Activity JSON to connected clients.
+func (s *Server) handleInject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var a Activity
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid activity: %s", err.Error()))
+		return
+	}
+	if err := a.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.Inject(a); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleDrop closes every Mercury connection.
+func (s *Server) handleDrop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.DropConnections()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,224 @@
+package mockmercury
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// helper: start a mock on a random port
+func startServer(t *testing.T, opts Options) *Server {
+	t.Helper()
+	s := New(opts)
+	if err := s.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// helper: register a device and return its Mercury URL
+func register(t *testing.T, s *Server, token string) string {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, s.URL()+DevicesPath, strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("register status = %d, want 200", resp.StatusCode)
+	}
+	var dev struct {
+		WebSocketURL string `json:"webSocketUrl"`
+		UserID       string `json:"userId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&dev); err != nil {
+		t.Fatalf("failed to decode device: %v", err)
+	}
+	if dev.UserID != DefaultMe.ID {
+		t.Errorf("userId = %q, want %q", dev.UserID, DefaultMe.ID)
+	}
+	return dev.WebSocketURL
+}
+
+// helper: connect and complete the Mercury handshake
+func connect(t *testing.T, wsURL, token string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := conn.WriteJSON(map[string]interface{}{
+		"id": "1", "type": "authorization", "data": map[string]string{"token": token},
+	}); err != nil {
+		t.Fatalf("auth write error: %v", err)
+	}
+	msg := readEvent(t, conn)
+	if msg.Data["eventType"] != "mercury.buffer_state" {
+		t.Fatalf("handshake reply = %v, want mercury.buffer_state", msg)
+	}
+	return conn
+}
+
+type wireEvent struct {
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) wireEvent {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg wireEvent
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	return msg
+}
+
+func TestServer_HandshakeAndInject(t *testing.T) {
+	s := startServer(t, Options{})
+	conn := connect(t, register(t, s, "any-token"), "any-token")
+	if err := s.WaitForConnections(1, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := Post("room-1", "hi")
+	reply.ParentID = "msg-0"
+	if err := s.Inject(reply); err != nil {
+		t.Fatalf("Inject() error: %v", err)
+	}
+
+	msg := readEvent(t, conn)
+	if msg.Data["eventType"] != "conversation.activity" {
+		t.Fatalf("eventType = %v, want conversation.activity", msg.Data["eventType"])
+	}
+	act := msg.Data["activity"].(map[string]interface{})
+	if act["verb"] != "post" {
+		t.Errorf("verb = %v, want post", act["verb"])
+	}
+	if obj := act["object"].(map[string]interface{}); obj["displayName"] != "hi" {
+		t.Errorf("object.displayName = %v, want hi", obj["displayName"])
+	}
+	if target := act["target"].(map[string]interface{}); target["id"] != "room-1" {
+		t.Errorf("target.id = %v, want room-1", target["id"])
+	}
+	if parent := act["parent"].(map[string]interface{}); parent["id"] != "msg-0" {
+		t.Errorf("parent.id = %v, want msg-0", parent["id"])
+	}
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	s := startServer(t, Options{Token: "good"})
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL()+APIPath+"/people/me", nil)
+	req.Header.Set("Authorization", "Bearer bad")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("people/me status = %d, want 401", resp.StatusCode)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL(), "http")+MercuryPath, nil)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.WriteJSON(map[string]interface{}{"type": "authorization", "data": map[string]string{"token": "bad"}})
+	if msg := readEvent(t, conn); msg.Type != "error" {
+		t.Errorf("handshake reply type = %q, want error", msg.Type)
+	}
+}
+
+func TestServer_MessageLookup(t *testing.T) {
+	s := startServer(t, Options{})
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL()+APIPath+"/messages/msg-1", nil)
+	req.Header.Set("Authorization", "Bearer any")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	defer resp.Body.Close()
+	var msg map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil || resp.StatusCode != http.StatusOK || msg["id"] != "msg-1" {
+		t.Errorf("messages/msg-1 = %d %v (%v)", resp.StatusCode, msg, err)
+	}
+}
+
+func TestServer_InjectWithoutClients(t *testing.T) {
+	s := startServer(t, Options{})
+	if err := s.Inject(Post("room-1", "hi")); err != ErrNoClients {
+		t.Errorf("Inject() error = %v, want ErrNoClients", err)
+	}
+}
+
+func TestServer_HTTPInjectAndDrop(t *testing.T) {
+	s := startServer(t, Options{})
+	conn := connect(t, register(t, s, "t"), "t")
+	if err := s.WaitForConnections(1, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(Activity{Verb: "add", RoomID: "room-1", PersonID: "p-1"})
+	resp, err := http.Post(s.URL()+InjectPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("inject status = %d, want 202", resp.StatusCode)
+	}
+	act := readEvent(t, conn).Data["activity"].(map[string]interface{})
+	if obj := act["object"].(map[string]interface{}); obj["id"] != "p-1" {
+		t.Errorf("object.id = %v, want p-1", obj["id"])
+	}
+
+	resp, err = http.Post(s.URL()+DropPath, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	resp.Body.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Error("connection should be closed after drop")
+	}
+	if s.Connections() != 0 {
+		t.Errorf("connections = %d, want 0", s.Connections())
+	}
+}
+
+func TestReadScript(t *testing.T) {
+	script := `# greet then react
+{"verb":"post","roomId":"room-1","text":"hello"}
+
+{"delay":"250ms","verb":"cardAction","roomId":"room-1","messageId":"card-1","inputs":{"ok":true}}
+`
+	steps, err := ReadScript(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("ReadScript() error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("steps = %d, want 2", len(steps))
+	}
+	if steps[0].Text != "hello" || steps[1].delay != 250*time.Millisecond || steps[1].MessageID != "card-1" {
+		t.Errorf("steps = %+v", steps)
+	}
+
+	for _, bad := range []string{`{"roomId":"r"}`, `{"verb":"post","delay":"soon"}`, `not json`} {
+		if _, err := ReadScript(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadScript(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+// Filter selects which events a client receives. An empty set matches
+// everything.
+type Filter struct {
+	Pipelines map[string]bool
+	Resources map[string]bool
+	Events    map[string]bool
+}
+
+// ParseFilter builds a Filter from the pipeline, resource and event query
+// parameters. Each accepts a comma-separated list and may be repeated.
+func ParseFilter(q url.Values) Filter {
+	return Filter{
+		Pipelines: parseSet(q["pipeline"]),
+		Resources: parseSet(q["resource"]),
+		Events:    parseSet(q["event"]),
+	}
+}
+
+// Matches reports whether an event from pipeline passes the filter.
+func (f Filter) Matches(pipeline string, event config.WebhookEvent) bool {
+	return matchSet(f.Pipelines, pipeline) &&
+		matchSet(f.Resources, event.Resource) &&
+		matchSet(f.Events, event.Event)
+}
+
+func parseSet(values []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				set[item] = true
+			}
+		}
+	}
+	return set
+}
+
+func matchSet(set map[string]bool, value string) bool {
+	return len(set) == 0 || set[value]
+}
+
+// Reasons a client is disconnected by the hub.
+const (
+	reasonSlowConsumer = "slow consumer"
+	reasonClientGone   = "client disconnected"
+	reasonShutdown     = "server shutting down"
+)
+
+// client is one connected WebSocket or SSE consumer.
+type client struct {
+	filter Filter
+	send   chan []byte
+
+	// done is closed when the hub drops the client, either because it fell
+	// too far behind or because the server is shutting down.
+	done      chan struct{}
+	closeOnce sync.Once
+	reason    string
+}
+
+func (c *client) close(reason string) {
+	c.closeOnce.Do(func() {
+		c.reason = reason
+		close(c.done)
+	})
+}
+
+// Hub fans events out to connected clients. Each client has a bounded
+// queue; a client whose queue is full is disconnected rather than allowed
+// to hold up the pipelines.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	buffer  int
+}
+
+// NewHub creates a Hub that queues up to buffer events per client.
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = config.DefaultServerBuffer
+	}
+	return &Hub{
+		clients: make(map[*client]struct{}),
+		buffer:  buffer,
+	}
+}
+
+// Observe implements listener.Observer by publishing the event to every
+// client whose filter matches.
+func (h *Hub) Observe(pipeline string, event config.WebhookEvent) {
+	var payload []byte
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if !c.filter.Matches(pipeline, event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				display.Println(display.Error(fmt.Sprintf("stream server: failed to marshal event: %s", err.Error())))
+				return
+			}
+		}
+		select {
+		case c.send <- payload:
+		default:
+			delete(h.clients, c)
+			c.close(reasonSlowConsumer)
+		}
+	}
+}
+
+// ClientCount returns the number of connected clients.
+func (h *Hub) ClientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// register adds a client with the given filter.
+func (h *Hub) register(filter Filter) *client {
+	c := &client{
+		filter: filter,
+		send:   make(chan []byte, h.buffer),
+		done:   make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+// unregister removes a client that has disconnected on its own.
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	c.close(reasonClientGone)
+}
+
+// closeAll disconnects every client.
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		delete(h.clients, c)
+		c.close(reasonShutdown)
+	}
+}
@@ -0,0 +1,327 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package server implements the optional local event stream server. Browser
+// dashboards and dev tools connect to /events over WebSocket or Server-Sent
+// Events and receive config.WebhookEvent JSON as pipelines forward it.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+)
+
+const (
+	// writeTimeout bounds a single write to a client. A client that cannot
+	// take a frame in this time is treated as gone.
+	writeTimeout = 10 * time.Second
+
+	// keepaliveInterval is how often idle connections get a ping (WebSocket)
+	// or comment line (SSE) so proxies do not time them out.
+	keepaliveInterval = 30 * time.Second
+)
+
+// Server serves /events to WebSocket and SSE clients.
+type Server struct {
+	hub      *Hub
+	listen   string
+	tokens   [][]byte
+	origins  map[string]bool // allowed cross-origin browser origins, lower-cased
+	upgrader websocket.Upgrader
+	httpSrv  *http.Server
+	listener net.Listener
+}
+
+// New creates a Server for opts, resolving the client tokens from their env
+// vars. Every env var listed in token_envs must be set, and at least one is
+// required unless the server listens on a loopback address.
+func New(opts *config.ServerOptions, hub *Hub) (*Server, error) {
+	s := &Server{
+		hub:     hub,
+		listen:  opts.Listen,
+		origins: make(map[string]bool),
+	}
+	// Without a token any page open in a local browser could read the
+	// stream, so browser requests are limited to known origins.
+	s.upgrader.CheckOrigin = s.originAllowed
+	if s.listen == "" {
+		s.listen = config.DefaultServerListen
+	}
+
+	for _, env := range opts.TokenEnvs {
+		token := os.Getenv(env)
+		if token == "" {
+			return nil, fmt.Errorf("stream server: env var %s is not set", env)
+		}
+		s.tokens = append(s.tokens, []byte(token))
+	}
+	if len(s.tokens) == 0 && !config.IsLoopbackListen(s.listen) {
+		return nil, fmt.Errorf("stream server: token_envs is required when listening on %s, which is not a loopback address", s.listen)
+	}
+	for _, origin := range opts.AllowedOrigins {
+		s.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", s.handleEvents)
+	s.httpSrv = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: writeTimeout,
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving /events.
+func (s *Server) Handler() http.Handler {
+	return s.httpSrv.Handler
+}
+
+// Start listens on the configured address and serves in the background.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.listen)
+	if err != nil {
+		return fmt.Errorf("stream server: failed to listen on %s: %w", s.listen, err)
+	}
+	s.listener = ln
+
+	go func() {
+		if err := s.httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			display.Println(display.Error(fmt.Sprintf("stream server: %s", err.Error())))
+		}
+	}()
+
+	display.Println(display.Info(fmt.Sprintf("stream server listening on %s (WebSocket and SSE at /events)",
+		display.Highlight(ln.Addr().String()))))
+	if len(s.tokens) == 0 {
+		display.Println(display.Info("stream server: no token_envs configured, local clients are not authenticated"))
+	}
+	return nil
+}
+
+// Addr returns the address the server is listening on, once started.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown disconnects all clients and stops the server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.hub.closeAll()
+	return s.httpSrv.Shutdown(ctx)
+}
+
+// handleEvents checks the client's origin, authenticates it and upgrades
+// to WebSocket when requested, falling back to SSE otherwise.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter := ParseFilter(r.URL.Query())
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r, filter)
+		return
+	}
+	s.serveSSE(w, r, filter)
+}
+
+// originAllowed reports whether a request may be served given its Host and
+// Origin headers. Non-browser clients send no Origin; browsers are allowed
+// from the server's own origin and from the configured allowed_origins.
+// Without tokens the Host must also name this machine, since a DNS
+// rebinding page controls both headers and would otherwise pass as
+// same-origin.
+func (s *Server) originAllowed(r *http.Request) bool {
+	if len(s.tokens) == 0 && !s.localHost(r.Host) {
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if s.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// localHost reports whether a Host header names this server directly:
+// localhost, a loopback IP or the configured listen host.
+func (s *Server) localHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	listenHost, _, err := net.SplitHostPort(s.listen)
+	return err == nil && listenHost != "" && strings.EqualFold(host, listenHost)
+}
+
+// authorized checks the bearer token or, for browser clients that cannot
+// set headers on WebSocket and EventSource requests, the token query
+// parameter.
+func (s *Server) authorized(r *http.Request) bool {
+	if len(s.tokens) == 0 {
+		return true
+	}
+
+	token := r.URL.Query().Get("token")
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		token = strings.TrimPrefix(h, "Bearer ")
+	}
+	if token == "" {
+		return false
+	}
+
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// serveWebSocket streams events as text frames until the client goes away
+// or the hub drops it.
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request, filter Filter) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	c := s.hub.register(filter)
+	defer s.hub.unregister(c)
+	logClient("websocket", r, "connected")
+
+	// Read (and discard) client frames so control frames are processed and
+	// a closed connection is noticed.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				s.hub.unregister(c)
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-c.done:
+			code := websocket.CloseNormalClosure
+			switch c.reason {
+			case reasonSlowConsumer:
+				code = websocket.CloseTryAgainLater
+			case reasonShutdown:
+				code = websocket.CloseGoingAway
+			}
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(code, c.reason), time.Now().Add(writeTimeout))
+			logClient("websocket", r, "disconnected: "+c.reason)
+			return
+		}
+	}
+}
+
+// serveSSE streams events as "data:" lines until the client goes away or
+// the hub drops it.
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request, filter Filter) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, args ...interface{}) error {
+		_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := write(": connected\n\n"); err != nil {
+		return
+	}
+
+	c := s.hub.register(filter)
+	defer s.hub.unregister(c)
+	logClient("sse", r, "connected")
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.send:
+			if err := write("data: %s\n\n", msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := write(": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-c.done:
+			_ = write("event: close\ndata: %s\n\n", c.reason)
+			logClient("sse", r, "disconnected: "+c.reason)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// logClient logs a client connecting or disconnecting with its filter
+// parameters. The token parameter is never logged.
+func logClient(kind string, r *http.Request, what string) {
+	q := r.URL.Query()
+	q.Del("token")
+	display.Println(display.Info(fmt.Sprintf("stream server: %s client %s %s (%s)",
+		kind, r.RemoteAddr, what, q.Encode())))
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// helper: start a stream server on httptest and return it with its hub
+func newTestServer(t *testing.T, opts *config.ServerOptions) (*httptest.Server, *Hub) {
+	t.Helper()
+	hub := NewHub(opts.Buffer)
+	s, err := New(opts, hub)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		hub.closeAll()
+		ts.Close()
+	})
+	return ts, hub
+}
+
+// helper: wait until the hub has n clients
+func waitForClients(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.ClientCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("client count = %d, want %d", hub.ClientCount(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func dialWS(t *testing.T, ts *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("websocket dial error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestParseFilter(t *testing.T) {
+	f := ParseFilter(url.Values{
+		"pipeline": {"bot"},
+		"resource": {"messages, rooms"},
+	})
+
+	tests := []struct {
+		pipeline string
+		resource string
+		want     bool
+	}{
+		{"bot", "messages", true},
+		{"bot", "rooms", true},
+		{"bot", "memberships", false},
+		{"other", "messages", false},
+	}
+	for _, tt := range tests {
+		got := f.Matches(tt.pipeline, config.WebhookEvent{Resource: tt.resource, Event: "created"})
+		if got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pipeline, tt.resource, got, tt.want)
+		}
+	}
+
+	if !ParseFilter(url.Values{}).Matches("any", config.WebhookEvent{Resource: "rooms"}) {
+		t.Error("empty filter should match everything")
+	}
+}
+
+func TestWebSocket_FilteredEvents(t *testing.T) {
+	ts, hub := newTestServer(t, &config.ServerOptions{})
+	conn := dialWS(t, ts, "pipeline=bot&resource=messages")
+	waitForClients(t, hub, 1)
+
+	hub.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "created"})
+	hub.Observe("other", config.WebhookEvent{Resource: "messages", Event: "created"})
+	hub.Observe("bot", config.WebhookEvent{Resource: "messages", Event: "deleted"})
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error: %v", err)
+	}
+	var event config.WebhookEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if event.Resource != "messages" || event.Event != "deleted" {
+		t.Errorf("event = %s:%s, want messages:deleted", event.Resource, event.Event)
+	}
+}
+
+func TestSSE_StreamsEvents(t *testing.T) {
+	ts, hub := newTestServer(t, &config.ServerOptions{})
+
+	resp, err := http.Get(ts.URL + "/events?event=created")
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "updated"})
+	hub.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "created"})
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var event config.WebhookEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		if event.Event != "created" {
+			t.Errorf("event = %q, want created", event.Event)
+		}
+		return
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	t.Setenv("DASHBOARD_TOKEN", "s3cret")
+	ts, _ := newTestServer(t, &config.ServerOptions{TokenEnvs: []string{"DASHBOARD_TOKEN"}})
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/events", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status with bearer token = %d, want 200", resp.StatusCode)
+	}
+
+	// Browser WebSocket clients pass the token as a query parameter
+	dialWS(t, ts, "token=s3cret")
+}
+
+func TestServer_MissingTokenEnv(t *testing.T) {
+	if _, err := New(&config.ServerOptions{TokenEnvs: []string{"HOOKBUSTER_UNSET_TOKEN"}}, NewHub(0)); err == nil {
+		t.Error("New() should fail when a token env var is not set")
+	}
+}
+
+func TestServer_RefusesCrossOrigin(t *testing.T) {
+	ts, _ := newTestServer(t, &config.ServerOptions{AllowedOrigins: []string{"http://localhost:3000"}})
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events"
+
+	// A page on another origin can neither open a WebSocket ...
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {"http://evil.example"}})
+	if err == nil {
+		t.Fatal("websocket dial from another origin should fail")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("websocket response = %v, want 403", resp)
+	}
+
+	// ... nor an EventSource
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/events", nil)
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("SSE status from another origin = %d, want 403", resp.StatusCode)
+	}
+
+	// Allowed and same-origin pages connect
+	for _, origin := range []string{"http://localhost:3000", ts.URL} {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {origin}})
+		if err != nil {
+			t.Errorf("websocket dial from %s error: %v", origin, err)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+func TestServer_UnauthenticatedDefaults(t *testing.T) {
+	// The default loopback listener may run without tokens, but browsers
+	// on other origins are still refused
+	hub := NewHub(0)
+	s, err := New(&config.ServerOptions{}, hub)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9090/events", nil)
+	req.Header.Set("Origin", "https://attacker.example")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+
+	// A DNS rebinding page controls both Host and Origin
+	for _, origin := range []string{"http://evil.example", ""} {
+		req = httptest.NewRequest(http.MethodGet, "http://evil.example/events", nil)
+		req.Host = "evil.example"
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec = httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("rebinding request with Origin %q: status = %d, want 403", origin, rec.Code)
+		}
+	}
+
+	for _, listen := range []string{"0.0.0.0:9090", ":9090", "192.168.1.10:9090"} {
+		if _, err := New(&config.ServerOptions{Listen: listen}, hub); err == nil {
+			t.Errorf("New(listen %q) without token_envs should fail", listen)
+		}
+	}
+	if _, err := New(&config.ServerOptions{Listen: "localhost:9090"}, hub); err != nil {
+		t.Errorf("New(localhost) error: %v", err)
+	}
+}
+
+func TestHub_DisconnectsSlowConsumer(t *testing.T) {
+	hub := NewHub(2)
+	slow := hub.register(Filter{})
+	other := hub.register(ParseFilter(url.Values{"resource": {"messages"}}))
+
+	// Nothing drains the queues, so the third event overflows the buffer
+	for i := 0; i < 3; i++ {
+		hub.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "created"})
+	}
+
+	select {
+	case <-slow.done:
+		if slow.reason != reasonSlowConsumer {
+			t.Errorf("reason = %q, want %q", slow.reason, reasonSlowConsumer)
+		}
+	default:
+		t.Fatal("slow consumer should be disconnected")
+	}
+
+	select {
+	case <-other.done:
+		t.Error("client filtered out of the events should stay connected")
+	default:
+	}
+	if hub.ClientCount() != 1 {
+		t.Errorf("client count = %d, want 1", hub.ClientCount())
+	}
+}
@@ -0,0 +1,202 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+// Package session records forwarded events to a JSON Lines file and replays
+// them later with their original timing, so a real Webex session can be
+// re-run against a local receiver without a token.
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+// Record is one line of a session file.
+type Record struct {
+	// OffsetMs is the time since recording started, in milliseconds.
+	OffsetMs int64               `json:"offset_ms"`
+	Pipeline string              `json:"pipeline,omitempty"`
+	Event    config.WebhookEvent `json:"event"`
+}
+
+// Recorder appends every observed event to a session file. It implements
+// listener.Observer.
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	enc     *json.Encoder
+	started time.Time
+	count   int
+}
+
+// NewRecorder creates (or truncates) the session file at path.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session file: %w", err)
+	}
+	return &Recorder{
+		file:    f,
+		enc:     json.NewEncoder(f),
+		started: time.Now(),
+	}, nil
+}
+
+// Observe writes the event with its offset from the start of recording.
+func (r *Recorder) Observe(pipeline string, event config.WebhookEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+
+	rec := Record{
+		OffsetMs: time.Since(r.started).Milliseconds(),
+		Pipeline: pipeline,
+		Event:    event,
+	}
+	if err := r.enc.Encode(rec); err == nil {
+		r.count++
+	}
+}
+
+// Count returns the number of events recorded so far.
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Close flushes and closes the session file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Load reads all records from a session file.
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read parses session records from r, one JSON object per line. Blank lines
+// are skipped.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("session line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return records, nil
+}
+
+// SpeedMax replays events back to back with no delay.
+const SpeedMax = 0
+
+// ParseSpeed parses a replay speed: "realtime" (1x), "max" (no delay) or a
+// multiplier such as "2x" or "0.5x".
+func ParseSpeed(s string) (float64, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "realtime", "":
+		return 1, nil
+	case "max":
+		return SpeedMax, nil
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(s), "x"), 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid speed %q (use realtime, max or a multiplier like 2x)", s)
+	}
+	return v, nil
+}
+
+// Replayer re-sends records in order, sleeping between them to reproduce
+// the recorded gaps scaled by Speed.
+type Replayer struct {
+	// Speed is the playback multiplier; SpeedMax sends without delay.
+	Speed float64
+
+	// Send delivers one record. Errors are counted and replay continues.
+	Send func(Record) error
+
+	// sleep is replaced in tests.
+	sleep func(time.Duration)
+}
+
+// Result summarises a replay.
+type Result struct {
+	Sent   int
+	Failed int
+}
+
+// Run replays records until done or stop is closed.
+func (p *Replayer) Run(records []Record, stop <-chan struct{}) Result {
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = func(d time.Duration) {
+			select {
+			case <-time.After(d):
+			case <-stop:
+			}
+		}
+	}
+
+	var res Result
+	var prev int64
+	for i, rec := range records {
+		if i > 0 && p.Speed != SpeedMax {
+			if gap := rec.OffsetMs - prev; gap > 0 {
+				sleep(time.Duration(float64(gap) / p.Speed * float64(time.Millisecond)))
+			}
+		}
+		prev = rec.OffsetMs
+
+		select {
+		case <-stop:
+			return res
+		default:
+		}
+
+		if err := p.Send(rec); err != nil {
+			res.Failed++
+		} else {
+			res.Sent++
+		}
+	}
+	return res
+}
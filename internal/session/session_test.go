@@ -0,0 +1,148 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder() error: %v", err)
+	}
+
+	rec.Observe("bot", config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}})
+	rec.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "updated"})
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	// Events after Close are dropped rather than panicking
+	rec.Observe("bot", config.WebhookEvent{Resource: "rooms", Event: "created"})
+
+	records, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(records) != 2 || rec.Count() != 2 {
+		t.Fatalf("records = %d (count %d), want 2", len(records), rec.Count())
+	}
+	if records[0].Pipeline != "bot" || records[0].Event.Resource != "messages" {
+		t.Errorf("first record = %+v, want bot messages", records[0])
+	}
+	if records[1].OffsetMs < records[0].OffsetMs {
+		t.Error("offsets should not go backwards")
+	}
+}
+
+func TestRead_ReportsBadLine(t *testing.T) {
+	input := `{"offset_ms":0,"event":{"resource":"rooms","event":"created"}}
+
+not json
+`
+	_, err := Read(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Read() error = %v, want line 3", err)
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"realtime", 1, false},
+		{"", 1, false},
+		{"max", SpeedMax, false},
+		{"2x", 2, false},
+		{"0.5x", 0.5, false},
+		{"3", 3, false},
+		{"0x", 0, true},
+		{"fast", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpeed(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSpeed(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseSpeed(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReplayer_ScalesGaps(t *testing.T) {
+	records := []Record{
+		{OffsetMs: 0, Event: config.WebhookEvent{Event: "a"}},
+		{OffsetMs: 1000, Event: config.WebhookEvent{Event: "b"}},
+		{OffsetMs: 3000, Event: config.WebhookEvent{Event: "c"}},
+	}
+
+	var sleeps []time.Duration
+	var sent []string
+	p := &Replayer{
+		Speed: 2,
+		Send: func(r Record) error {
+			sent = append(sent, r.Event.Event)
+			return nil
+		},
+		sleep: func(d time.Duration) { sleeps = append(sleeps, d) },
+	}
+	res := p.Run(records, make(chan struct{}))
+
+	if res.Sent != 3 || res.Failed != 0 {
+		t.Errorf("result = %+v, want 3 sent", res)
+	}
+	if strings.Join(sent, "") != "abc" {
+		t.Errorf("order = %v, want a b c", sent)
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if len(sleeps) != 2 || sleeps[0] != want[0] || sleeps[1] != want[1] {
+		t.Errorf("sleeps = %v, want %v", sleeps, want)
+	}
+}
+
+func TestReplayer_MaxSpeedAndFailures(t *testing.T) {
+	records := []Record{{OffsetMs: 0}, {OffsetMs: 60000}}
+
+	calls := 0
+	p := &Replayer{
+		Speed: SpeedMax,
+		Send: func(Record) error {
+			calls++
+			if calls == 2 {
+				return errTest
+			}
+			return nil
+		},
+		sleep: func(time.Duration) { t.Error("max speed should not sleep") },
+	}
+	res := p.Run(records, make(chan struct{}))
+	if res.Sent != 1 || res.Failed != 1 {
+		t.Errorf("result = %+v, want 1 sent 1 failed", res)
+	}
+}
+
+func TestReplayer_Stop(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+
+	p := &Replayer{
+		Speed: 1,
+		Send:  func(Record) error { t.Error("nothing should be sent after stop"); return nil },
+		sleep: func(time.Duration) {},
+	}
+	res := p.Run([]Record{{}, {}}, stop)
+	if res.Sent != 0 {
+		t.Errorf("sent = %d, want 0", res.Sent)
+	}
+}
+
+var errTest = errors.New("boom")
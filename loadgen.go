@@ -0,0 +1,229 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/loadgen"
+)
+
+// maxReportedErrors caps how many distinct errors are listed per sink.
+const maxReportedErrors = 5
+
+// runLoadgen implements "hookbuster loadgen". It synthesizes events and
+// drives them through the targets of the pipelines in a config (or through
+// --target URLs) with the same senders and balancer used for live traffic,
+// then reports throughput, latency percentiles and errors per pipeline.
+func runLoadgen(args []string) {
+	fs := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	configPath := fs.String("c", "", "drive the targets of the pipelines in this hookbuster.yml")
+	var pipelines, targetURLs stringList
+	fs.Var(&pipelines, "pipeline", "only load this pipeline from -c (repeatable)")
+	fs.Var(&targetURLs, "target", "target URL to load instead of -c (repeatable)")
+	mode := fs.String("mode", config.ModeRoundRobin, "forwarding mode for --target: roundrobin or fanout")
+	rate := fs.Float64("rate", 100, "events generated per second (0 = as fast as the targets accept)")
+	duration := fs.Duration("duration", 10*time.Second, "how long to generate load (0 = until --count or Ctrl+C)")
+	count := fs.Int("count", 0, "stop after this many events (0 = no limit)")
+	concurrency := fs.Int("concurrency", 8, "sends in flight per pipeline")
+	mixSpec := fs.String("mix", "", "event distribution, e.g. messages:created=80,memberships=20 (default: uniform)")
+	seed := fs.Int64("seed", 0, "random seed for a reproducible event sequence")
+	_ = fs.Parse(args)
+
+	if (*configPath == "") == (len(targetURLs) == 0) {
+		display.Println(display.Error("usage: hookbuster loadgen (-c hookbuster.yml | --target URL) [--rate 100] [--duration 10s] [--mix ...]"))
+		os.Exit(exitUsage)
+	}
+	if *rate < 0 || *concurrency < 1 || *count < 0 || *duration < 0 {
+		display.Println(display.Error("--rate, --duration and --count must not be negative and --concurrency must be at least 1"))
+		os.Exit(exitUsage)
+	}
+	mix, err := loadgen.ParseMix(*mixSpec)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitUsage)
+	}
+
+	var sinks []loadgen.Sink
+	var closers []func()
+	defer func() {
+		for _, c := range closers {
+			c()
+		}
+	}()
+
+	if *configPath != "" {
+		var cfg *config.HookbusterConfig
+		cfg, err = config.LoadConfig(*configPath)
+		if err != nil {
+			printConfigError(*configPath, err)
+			os.Exit(exitConfig)
+		}
+		sinks, closers, err = configSinks(cfg, *configPath, pipelines)
+	} else {
+		sinks, closers, err = targetSinks(*mode, targetURLs)
+	}
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	if len(sinks) == 0 {
+		display.Println(display.Error("no pipelines with targets to load"))
+		os.Exit(exitFailure)
+	}
+
+	stop := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		close(stop)
+	}()
+
+	rateDesc := fmt.Sprintf("%g events/s", *rate)
+	if *rate == 0 {
+		rateDesc = "unthrottled"
+	}
+	display.Println(display.Info(fmt.Sprintf("generating load at %s for %s across %d pipeline(s), %d in flight each",
+		display.Highlight(rateDesc), describeBound(*duration, *count), len(sinks), *concurrency)))
+
+	runner := &loadgen.Runner{
+		Rate:        *rate,
+		Duration:    *duration,
+		Count:       *count,
+		Concurrency: *concurrency,
+		Mix:         mix,
+		Sinks:       sinks,
+		Seed:        *seed,
+	}
+	reports := runner.Run(stop)
+
+	failed := false
+	for _, r := range reports {
+		printLoadReport(r)
+		if r.Failed > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		for _, c := range closers {
+			c()
+		}
+		os.Exit(exitFailure)
+	}
+}
+
+// configSinks builds one sink per pipeline in the config (optionally only
+// the named ones), subscribed to the same resources and events as the live
+// pipeline. Pipelines without targets are skipped.
+func configSinks(cfg *config.HookbusterConfig, path string, only []string) ([]loadgen.Sink, []func(), error) {
+	wanted := make(map[string]bool)
+	for _, name := range only {
+		wanted[name] = true
+	}
+
+	var sinks []loadgen.Sink
+	var closers []func()
+	for _, p := range cfg.Pipelines {
+		if len(wanted) > 0 && !wanted[p.Name] {
+			continue
+		}
+		delete(wanted, p.Name)
+		if len(p.Targets) == 0 {
+			display.Println(display.Info(fmt.Sprintf("[%s] has no targets, skipping", p.Name)))
+			continue
+		}
+
+		mode := p.Mode
+		if mode == "" {
+			mode = config.ModeRoundRobin
+		}
+		send, closeTargets, err := newTargetSend(p.Name, mode, p.Targets)
+		if err != nil {
+			return nil, closers, fmt.Errorf("pipeline %q: %w", p.Name, err)
+		}
+		closers = append(closers, closeTargets)
+		sinks = append(sinks, loadgen.Sink{
+			Name:    p.Name,
+			Accepts: pipelineAccepts(p),
+			Send:    send,
+		})
+	}
+
+	for name := range wanted {
+		return nil, closers, fmt.Errorf("no pipeline named %q in %s", name, path)
+	}
+	return sinks, closers, nil
+}
+
+// pipelineAccepts mirrors the subscriptions startPipeline would make.
+func pipelineAccepts(p config.Pipeline) func(loadgen.Kind) bool {
+	sel := p.Selection()
+	return func(k loadgen.Kind) bool {
+		return sel.Matches(k.Resource, k.Event)
+	}
+}
+
+// targetSinks builds a single sink over ad-hoc target URLs.
+func targetSinks(mode string, urls []string) ([]loadgen.Sink, []func(), error) {
+	if !config.ValidModes[mode] {
+		return nil, nil, fmt.Errorf("unknown mode %q (valid: %s, %s)", mode, config.ModeFanout, config.ModeRoundRobin)
+	}
+	targets := make([]config.Target, len(urls))
+	for i, u := range urls {
+		targets[i] = config.Target{URL: u}
+	}
+	send, closeTargets, err := newTargetSend("loadgen", mode, targets)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []loadgen.Sink{{Name: "loadgen", Send: send}}, []func(){closeTargets}, nil
+}
+
+func describeBound(d time.Duration, count int) string {
+	switch {
+	case d > 0 && count > 0:
+		return fmt.Sprintf("%s or %d events", d, count)
+	case count > 0:
+		return fmt.Sprintf("%d events", count)
+	case d > 0:
+		return d.String()
+	}
+	return "until Ctrl+C"
+}
+
+// printLoadReport prints a sink's throughput, latency and error summary.
+func printLoadReport(r loadgen.Report) {
+	display.Println(display.Info(fmt.Sprintf("[%s] %d sent, %d failed (%.2f%%) in %s → %s",
+		r.Name, r.Sent, r.Failed, r.ErrorRate()*100, r.Elapsed.Round(time.Millisecond),
+		display.Highlight(fmt.Sprintf("%.1f events/s", r.Throughput)))))
+	display.Println(display.Info(fmt.Sprintf("[%s] latency p50 %s  p90 %s  p99 %s  max %s",
+		r.Name, fmtLatency(r.P50), fmtLatency(r.P90), fmtLatency(r.P99), fmtLatency(r.Max))))
+
+	for i, e := range r.Errors {
+		if i == maxReportedErrors {
+			display.Println(display.Error(fmt.Sprintf("[%s] … and %d more distinct error(s)", r.Name, len(r.Errors)-maxReportedErrors)))
+			break
+		}
+		display.Println(display.Error(fmt.Sprintf("[%s] %d× %s", r.Name, e.Count, e.Message)))
+	}
+}
+
+func fmtLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
@@ -5,23 +5,109 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/tejzpr/webex-go-hookbuster/internal/cli"
 	"github.com/tejzpr/webex-go-hookbuster/internal/config"
 	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
 	"github.com/tejzpr/webex-go-hookbuster/internal/listener"
+	"github.com/tejzpr/webex-go-hookbuster/internal/metadata"
+	"github.com/tejzpr/webex-go-hookbuster/internal/server"
 )
 
+// Version is set at build time with -ldflags "-X main.Version=v1.2.3".
+var Version = "dev"
+
+// Exit codes shared by every subcommand.
+const (
+	exitFailure = 1 // the command ran and failed: bad token, unreachable target, failed sends
+	exitUsage   = 2 // unknown command, bad flags or arguments
+	exitConfig  = 3 // the config file is missing or invalid
+)
+
+// command is one "hookbuster <name>" subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands lists the subcommands in the order shown by usage.
+var commands = []command{
+	{"run", "listen and forward events (config file, env vars or interactive)", runRun},
+	{"validate", "check a hookbuster.yml, its token env vars and targets without connecting", runValidate},
+	{"test-target", "send a sample event to a target URL and report the response", runTestTarget},
+	{"whoami", "show the Webex identity behind a token", runWhoami},
+	{"record", "record forwarded events to a session file", runRecord},
+	{"replay", "replay a recorded session to targets", runReplay},
+	{"loadgen", "benchmark targets with synthetic events", runLoadgen},
+	{"mock", "serve offline mock Webex endpoints for testing", runMock},
+	{"version", "print the hookbuster version", runVersion},
+}
+
 func main() {
-	configPath := flag.String("c", "", "path to hookbuster.yml config file")
-	flag.Parse()
+	args := os.Args[1:]
+
+	// No subcommand, or flags only (e.g. "hookbuster -c file"), means run so
+	// existing deployments and the Docker entrypoint keep working.
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && !isHelpFlag(args[0]) {
+		runRun(args)
+		return
+	}
+	if isHelpFlag(args[0]) || args[0] == "help" {
+		usage(os.Stdout)
+		return
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			c.run(args[1:])
+			return
+		}
+	}
+
+	display.Println(display.Error(fmt.Sprintf("unknown command %q", args[0])))
+	usage(os.Stderr)
+	os.Exit(exitUsage)
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// usage prints the command list.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: hookbuster <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "hookbuster <command> -h" for a command's flags. With no command, hookbuster runs.`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes: 0 success, 1 failure, 2 usage error, 3 invalid config.")
+}
+
+// runRun implements "hookbuster run". With -c (or HOOKBUSTER_CONFIG) it runs
+// the config's pipelines; otherwise TOKEN and PORT select deployment mode,
+// and without them it prompts interactively.
+func runRun(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	configPath := fs.String("c", "", "path to hookbuster.yml config file")
+	_ = fs.Parse(args)
 
 	if *configPath == "" {
 		*configPath = os.Getenv("HOOKBUSTER_CONFIG")
@@ -29,8 +115,9 @@ func main() {
 
 	if *configPath != "" {
 		// ── Config file mode (multi-pipeline) ───────────────────────────
+		cfg := loadRunConfig(*configPath)
 		display.Welcome()
-		runConfigMode(*configPath)
+		runConfigMode(*configPath, cfg)
 	} else {
 		tokenEnv := os.Getenv("TOKEN")
 		portEnv := os.Getenv("PORT")
@@ -46,13 +133,20 @@ func main() {
 	}
 }
 
+// runVersion implements "hookbuster version".
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	_ = fs.Parse(args)
+	fmt.Printf("hookbuster %s (%s, %s/%s)\n", Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
 // runDeploymentMode uses environment variables to configure hookbuster and
 // subscribes to ALL resources with ALL events (firehose mode).
 func runDeploymentMode(token, portStr string) {
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
-		fmt.Println(display.Error("PORT is not a valid number"))
-		os.Exit(1)
+		display.Println(display.Error("PORT is not a valid number"))
+		os.Exit(exitConfig)
 	}
 
 	target := os.Getenv("TARGET")
@@ -63,97 +157,118 @@ func runDeploymentMode(token, portStr string) {
 	// Verify the token
 	person, err := listener.VerifyAccessToken(token)
 	if err != nil {
-		fmt.Println(display.Error(err.Error()))
-		os.Exit(1)
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
 	}
-	fmt.Println(display.Info(fmt.Sprintf("token authenticated as %s", person.DisplayName)))
-	fmt.Println(display.Info(fmt.Sprintf("forwarding target set as %s", target)))
+	display.Println(display.Info(fmt.Sprintf("token authenticated as %s", person.DisplayName)))
+	display.Println(display.Info(fmt.Sprintf("forwarding target set as %s", target)))
 
 	specs := &config.Specs{
 		Target:      target,
 		AccessToken: token,
 		Port:        port,
-		Selection: config.Selection{
-			Event: "all",
-		},
+		Selection:   config.FirehoseSelection(),
 	}
 
 	l, err := listener.NewListener(specs)
 	if err != nil {
-		fmt.Println(display.Error(err.Error()))
-		os.Exit(1)
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
 	}
 
 	// Register all firehose resources
-	for _, resName := range config.FirehoseResourceNames {
-		res := config.Resources[resName]
-		if err := l.Start(res, "all"); err != nil {
-			fmt.Println(display.Error(err.Error()))
-			os.Exit(1)
-		}
+	if err := l.StartSelection(specs.Selection); err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
 	}
 
 	// Wait for SIGINT / SIGTERM
 	waitForShutdown(l)
 }
 
-// runInteractiveMode guides the user through CLI prompts.
-func runInteractiveMode() {
-	specs := &config.Specs{}
-
-	// 1. Gather access token (retry on failure)
-	gatherToken(specs)
-
-	// 2. Gather target
-	gatherTarget(specs)
-
-	// 3. Gather port
-	gatherPort(specs)
-
-	// 4. Gather resource (and event)
-	l := gatherResourceAndStart(specs)
-
-	// Wait for SIGINT / SIGTERM
-	waitForShutdown(l)
-}
-
 // pipelineErrFmt is the format string for pipeline error messages.
 const pipelineErrFmt = "pipeline %q: %s"
 
-// runConfigMode loads a YAML config file and starts one listener per pipeline.
-// Each pipeline connects with its own Webex token and forwards events to its
-// configured target(s), supporting both multi-token and fan-out patterns.
-func runConfigMode(path string) {
+// loadRunConfig loads a YAML config file to run, exiting on errors. When a
+// pipeline has a stdout:// target, every log line from here on, the banner
+// included, goes to stderr so stdout carries nothing but events.
+func loadRunConfig(path string) *config.HookbusterConfig {
 	cfg, err := config.LoadConfig(path)
 	if err != nil {
-		fmt.Println(display.Error(err.Error()))
-		os.Exit(1)
+		printConfigError(path, err)
+		os.Exit(exitConfig)
+	}
+	redirectLogsForStdoutTargets(cfg)
+	return cfg
+}
+
+// redirectLogsForStdoutTargets moves log lines to stderr when any pipeline
+// in cfg writes events to stdout.
+func redirectLogsForStdoutTargets(cfg *config.HookbusterConfig) {
+	for _, p := range cfg.Pipelines {
+		if forwarder.WritesStdout(p.Targets) {
+			display.SetOutput(os.Stderr)
+			return
+		}
 	}
+}
 
-	fmt.Println(display.Info(fmt.Sprintf("loaded config with %d pipeline(s) from %s", len(cfg.Pipelines), path)))
+// runConfigMode starts one listener per pipeline of cfg, loaded from path
+// by loadRunConfig. Extra observers (e.g. a session recorder) receive every
+// pipeline's events. Each pipeline connects with its own Webex token and
+// forwards events to its configured target(s), supporting both multi-token
+// and fan-out patterns.
+func runConfigMode(path string, cfg *config.HookbusterConfig, observers ...listener.Observer) {
+	printConfigWarnings(path, cfg)
+
+	display.Println(display.Info(fmt.Sprintf("loaded config with %d pipeline(s) from %s", len(cfg.Pipelines), path)))
+
+	var srv *server.Server
+	if cfg.Server != nil {
+		hub := server.NewHub(cfg.Server.Buffer)
+		observers = append(observers, hub)
+		var err error
+		srv, err = server.New(cfg.Server, hub)
+		if err == nil {
+			err = srv.Start()
+		}
+		if err != nil {
+			display.Println(display.Error(err.Error()))
+			os.Exit(exitFailure)
+		}
+	}
 
 	var listeners []*listener.Listener
 	for _, p := range cfg.Pipelines {
-		l := startPipeline(p)
+		token := os.Getenv(p.TokenEnv)
+		if token == "" {
+			display.Println(display.Error(fmt.Sprintf("pipeline %q: env var %s is not set", p.Name, p.TokenEnv)))
+			os.Exit(exitConfig)
+		}
+		l := startPipeline(p, p.Selection(), token, observers, cfg.Cache)
 		listeners = append(listeners, l)
 	}
 
 	waitForMultiShutdown(listeners)
-}
 
-// startPipeline resolves the token, verifies it, creates a listener and starts
-// subscriptions for a single pipeline from the config file.
-func startPipeline(p config.Pipeline) *listener.Listener {
-	token := os.Getenv(p.TokenEnv)
-	if token == "" {
-		fmt.Println(display.Error(fmt.Sprintf("pipeline %q: env var %s is not set", p.Name, p.TokenEnv)))
-		os.Exit(1)
+	if srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			display.Println(display.Error(fmt.Sprintf("error stopping stream server: %s", err.Error())))
+		}
 	}
+}
 
+// startPipeline verifies the pipeline's token, creates a listener and starts
+// the subscriptions in sel for a single pipeline. Observers such as the
+// stream server hub also receive the pipeline's events. A pipeline that
+// enriches events gets its own metadata cache, tuned by cacheOpts.
+func startPipeline(p config.Pipeline, sel config.Selection, token string, observers []listener.Observer, cacheOpts *config.CacheOptions) *listener.Listener {
 	person, err := listener.VerifyAccessToken(token)
 	if err != nil {
-		fmt.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
-		os.Exit(1)
+		display.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
+		os.Exit(exitFailure)
 	}
 
 	targetURLs := make([]string, len(p.Targets))
@@ -164,35 +279,47 @@ func startPipeline(p config.Pipeline) *listener.Listener {
 	if mode == "" {
 		mode = config.ModeRoundRobin
 	}
-	fmt.Println(display.Info(
+	forwardingTo := strings.Join(targetURLs, ", ")
+	if len(targetURLs) == 0 {
+		forwardingTo = "no targets (stream server / recorder only)"
+	}
+	display.Println(display.Info(
 		fmt.Sprintf("[%s] authenticated as %s → mode: %s → forwarding to %s",
-			p.Name, person.DisplayName, mode, strings.Join(targetURLs, ", ")),
+			p.Name, person.DisplayName, mode, forwardingTo),
 	))
 
 	l, err := listener.NewPipelineListener(p.Name, token, p.Mode, p.Targets)
 	if err != nil {
-		fmt.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
-		os.Exit(1)
+		display.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
+		os.Exit(exitFailure)
 	}
-
-	resources := p.Resources
-	if len(resources) == 0 {
-		resources = config.FirehoseResourceNames
+	for _, o := range observers {
+		l.AddObserver(o)
 	}
-
-	events := p.Events
-	if events == "" {
-		events = "all"
+	ignored := p.IgnoreActors
+	if p.IgnoreSelf {
+		ignored = append(append([]string{person.ID}, person.Emails...), ignored...)
+		display.Println(display.Info(fmt.Sprintf("[%s] ignoring activities by %s", p.Name, person.DisplayName)))
 	}
-
-	for _, resName := range resources {
-		res := config.Resources[resName]
-		if err := l.Start(res, events); err != nil {
-			fmt.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
-			os.Exit(1)
+	l.IgnoreActors(ignored...)
+	l.SetIDForm(p.IDs)
+	if p.Enrich {
+		l.SetMetadataCache(metadata.New(cacheOpts))
+	}
+	if p.Attachments != nil {
+		if err := l.SetAttachments(p.Attachments); err != nil {
+			display.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
+			_ = l.Stop()
+			os.Exit(exitFailure)
 		}
 	}
 
+	if err := l.StartSelection(sel); err != nil {
+		display.Println(display.Error(fmt.Sprintf(pipelineErrFmt, p.Name, err.Error())))
+		_ = l.Stop()
+		os.Exit(exitFailure)
+	}
+
 	return l
 }
 
@@ -207,14 +334,18 @@ func retryWithValidation[T any](
 ) {
 	for {
 		value, err := getValue()
+		if errors.Is(err, cli.ErrEOF) {
+			display.Println(display.Error("input closed"))
+			os.Exit(exitUsage)
+		}
 		if err != nil {
-			fmt.Println(display.Error(err.Error()))
+			display.Println(display.Error(err.Error()))
 			continue
 		}
 
 		if validate != nil {
 			if err := validate(value); err != nil {
-				fmt.Println(display.Error(err.Error()))
+				display.Println(display.Error(err.Error()))
 				continue
 			}
 		}
@@ -226,111 +357,23 @@ func retryWithValidation[T any](
 	}
 }
 
-func gatherToken(specs *config.Specs) {
+// gatherToken prompts until the user enters a token that verifies.
+func gatherToken() string {
+	var token string
 	retryWithValidation(
 		func() (string, error) { return cli.RequestToken() },
-		func(token string) error {
-			person, err := listener.VerifyAccessToken(token)
+		func(answer string) error {
+			person, err := listener.VerifyAccessToken(answer)
 			if err != nil {
 				return err
 			}
-			fmt.Println(display.Info(fmt.Sprintf("token authenticated as %s", person.DisplayName)))
-			specs.AccessToken = token
-			return nil
-		},
-		nil,
-	)
-}
-
-func gatherTarget(specs *config.Specs) {
-	retryWithValidation(
-		func() (string, error) { return cli.RequestTarget() },
-		func(target string) error {
-			if len(target) == 0 {
-				return fmt.Errorf(`not a valid target. target must be "localhost", a valid IP address, or hostname`)
-			}
-			fmt.Println(display.Answer(target))
-			specs.Target = target
+			display.Println(display.Info(fmt.Sprintf("token authenticated as %s", person.DisplayName)))
+			token = answer
 			return nil
 		},
 		nil,
 	)
-}
-
-func gatherPort(specs *config.Specs) {
-	retryWithValidation(
-		func() (int, error) { return cli.RequestPort() },
-		func(port int) error {
-			fmt.Println(display.Answer(fmt.Sprintf("%d", port)))
-			specs.Port = port
-			return nil
-		},
-		nil,
-	)
-}
-
-func gatherResourceAndStart(specs *config.Specs) *listener.Listener {
-	for {
-		result, err := cli.RequestResource()
-		if err != nil {
-			fmt.Println(display.Error(err.Error()))
-			continue
-		}
-
-		l := createListener(specs)
-
-		if result.AllResources {
-			startFirehose(l, specs)
-			return l
-		}
-
-		startSingleResource(l, specs, result.Single)
-		return l
-	}
-}
-
-func createListener(specs *config.Specs) *listener.Listener {
-	l, err := listener.NewListener(specs)
-	if err != nil {
-		fmt.Println(display.Error(err.Error()))
-		os.Exit(1)
-	}
-	return l
-}
-
-func startFirehose(l *listener.Listener, specs *config.Specs) {
-	specs.Selection.Event = "all"
-	for _, resName := range config.FirehoseResourceNames {
-		res := config.Resources[resName]
-		if err := l.Start(res, "all"); err != nil {
-			fmt.Println(display.Error(err.Error()))
-			os.Exit(1)
-		}
-	}
-}
-
-func startSingleResource(l *listener.Listener, specs *config.Specs, res *config.Resource) {
-	fmt.Println(display.Answer(res.Description))
-	specs.Selection.Resource = res.Description
-
-	event := gatherEvent(res)
-	specs.Selection.Event = event
-
-	if err := l.Start(res, event); err != nil {
-		fmt.Println(display.Error(err.Error()))
-		os.Exit(1)
-	}
-}
-
-func gatherEvent(resource *config.Resource) string {
-	for {
-		event, err := cli.RequestEvent(resource.Events)
-		if err != nil {
-			fmt.Println(display.Error(err.Error()))
-			continue
-		}
-		return event
-	}
+	return token
 }
 
 // ── Shutdown ────────────────────────────────────────────────────────────
@@ -340,16 +383,16 @@ func waitForShutdown(l *listener.Listener) {
 }
 
 func waitForMultiShutdown(listeners []*listener.Listener) {
-	fmt.Println(display.Info("Press Ctrl+C to exit."))
+	display.Println(display.Info("Press Ctrl+C to exit."))
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	<-sigCh
 
-	fmt.Println()
+	display.Println("")
 	for _, l := range listeners {
 		if err := l.Stop(); err != nil {
-			fmt.Println(display.Error(fmt.Sprintf("error stopping listener: %s", err.Error())))
+			display.Println(display.Error(fmt.Sprintf("error stopping listener: %s", err.Error())))
 		}
 	}
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+)
+
+func TestStdoutTarget_StdoutHoldsOnlyJSON(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hookbuster.yml")
+	yaml := `
+pipelines:
+  - name: "audit"
+    token_env: "WEBEX_TOKEN"
+    mode: "fanout"
+    targets:
+      - url: "stdout://"
+      - url: "` + receiver.URL + `"
+`
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stand-ins for the process's stdout and stderr
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	display.SetOutput(stdout)
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = origStdout, origStderr
+		display.SetOutput(origStdout)
+	})
+
+	// The same order as "hookbuster run -c": config, banner, then events
+	cfg := loadRunConfig(path)
+	display.Welcome()
+	senders, err := forwarder.NewSenders("audit", cfg.Pipelines[0].Targets)
+	if err != nil {
+		t.Fatalf("NewSenders() error: %v", err)
+	}
+	event := config.WebhookEvent{Resource: "messages", Event: "created", Data: map[string]interface{}{"id": "m1"}}
+	for _, s := range senders {
+		if err := s.Send(event); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+		s.Close()
+	}
+	if err := forwarder.ForwardToURL(receiver.URL, event); err != nil {
+		t.Fatalf("ForwardToURL() error: %v", err)
+	}
+
+	if _, err := stdout.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		lines++
+		if !json.Valid(scanner.Bytes()) {
+			t.Errorf("stdout line %d is not JSON: %q", lines, scanner.Text())
+		}
+	}
+	if lines != 1 {
+		t.Errorf("stdout has %d lines, want the one event", lines)
+	}
+}
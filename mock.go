@@ -0,0 +1,81 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/listener"
+	"github.com/tejzpr/webex-go-hookbuster/internal/mockmercury"
+)
+
+// runMock implements "hookbuster mock". It serves mock device registration,
+// Mercury and people/me endpoints so another hookbuster can run against it
+// with no network, optionally playing a script of activities once the first
+// client connects. Activities can also be injected with POST /mock/activities.
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:8765", "address to serve the mock endpoints on")
+	token := fs.String("token", "", "only accept this access token (default: accept any)")
+	scriptPath := fs.String("script", "", "JSON Lines file of activities to inject once a client connects")
+	_ = fs.Parse(args)
+
+	var steps []mockmercury.Step
+	if *scriptPath != "" {
+		f, err := os.Open(*scriptPath)
+		if err != nil {
+			display.Println(display.Error(fmt.Sprintf("failed to open script: %s", err.Error())))
+			os.Exit(exitFailure)
+		}
+		steps, err = mockmercury.ReadScript(f)
+		f.Close()
+		if err != nil {
+			display.Println(display.Error(err.Error()))
+			os.Exit(exitFailure)
+		}
+	}
+
+	m := mockmercury.New(mockmercury.Options{Token: *token})
+	if err := m.Start(*listen); err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	defer m.Close()
+
+	display.Welcome()
+	display.Println(display.Info(fmt.Sprintf("mock Webex listening on %s", display.Highlight(m.URL()))))
+	display.Println(display.Info("point hookbuster at it with:"))
+	fmt.Printf("  export %s=%s%s\n", listener.EnvAPIURL, m.URL(), mockmercury.APIPath)
+	fmt.Printf("  export %s=%s%s\n", listener.EnvWDMURL, m.URL(), mockmercury.DevicesPath)
+	display.Println(display.Info(fmt.Sprintf("inject activities with POST %s%s", m.URL(), mockmercury.InjectPath)))
+
+	stop := make(chan struct{})
+	if len(steps) > 0 {
+		go func() {
+			if err := m.WaitForConnections(1, 24*time.Hour); err != nil {
+				return
+			}
+			display.Println(display.Info(fmt.Sprintf("client connected, playing %d scripted activities", len(steps))))
+			if err := m.Play(steps, stop); err != nil {
+				display.Println(display.Error(err.Error()))
+				return
+			}
+			display.Println(display.Info("script finished"))
+		}()
+	}
+
+	display.Println(display.Info("Press Ctrl+C to exit."))
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+	close(stop)
+	fmt.Println()
+}
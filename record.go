@@ -0,0 +1,77 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/listener"
+	"github.com/tejzpr/webex-go-hookbuster/internal/session"
+)
+
+// runRecord implements "hookbuster record". With -c it runs the config's
+// pipelines as usual and records everything they forward; otherwise it
+// listens to the firehose for the TOKEN env var and only records.
+func runRecord(args []string) {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	out := fs.String("o", "session.jsonl", "session file to write")
+	configPath := fs.String("c", "", "record the pipelines in this hookbuster.yml while forwarding as usual")
+	_ = fs.Parse(args)
+
+	rec, err := session.NewRecorder(*out)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	defer func() {
+		if err := rec.Close(); err != nil {
+			display.Println(display.Error(fmt.Sprintf("failed to close session file: %s", err.Error())))
+		}
+		display.Println(display.Info(fmt.Sprintf("recorded %d event(s) to %s", rec.Count(), *out)))
+	}()
+
+	var cfg *config.HookbusterConfig
+	if *configPath != "" {
+		cfg = loadRunConfig(*configPath)
+	}
+	display.Welcome()
+	display.Println(display.Info(fmt.Sprintf("recording events to %s", display.Highlight(*out))))
+
+	if cfg != nil {
+		runConfigMode(*configPath, cfg, rec)
+		return
+	}
+
+	token := os.Getenv("TOKEN")
+	if token == "" {
+		display.Println(display.Error("set TOKEN or pass -c hookbuster.yml to record"))
+		os.Exit(exitFailure)
+	}
+
+	person, err := listener.VerifyAccessToken(token)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	display.Println(display.Info(fmt.Sprintf("token authenticated as %s", person.DisplayName)))
+
+	l, err := listener.NewPipelineListener("record", token, "", nil)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	l.AddObserver(rec)
+
+	if err := l.StartSelection(config.FirehoseSelection()); err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+
+	waitForShutdown(l)
+}
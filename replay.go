@@ -0,0 +1,169 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+	"github.com/tejzpr/webex-go-hookbuster/internal/session"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+// runReplay implements "hookbuster replay session.jsonl --target URL". The
+// recorded events are re-sent through the same senders and balancer as a
+// live pipeline, so any target kind works.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	var targetURLs stringList
+	fs.Var(&targetURLs, "target", "target URL to replay to (repeat for several targets)")
+	speedStr := fs.String("speed", "realtime", "playback speed: realtime, max or a multiplier like 2x")
+	mode := fs.String("mode", config.ModeRoundRobin, "forwarding mode for several targets: roundrobin or fanout")
+
+	path, err := parseWithPositional(fs, args)
+	if err != nil || path == "" {
+		display.Println(display.Error("usage: hookbuster replay session.jsonl --target URL [--speed 2x|realtime|max]"))
+		os.Exit(exitUsage)
+	}
+	if len(targetURLs) == 0 {
+		display.Println(display.Error("at least one --target is required"))
+		os.Exit(exitUsage)
+	}
+	if !config.ValidModes[*mode] {
+		display.Println(display.Error(fmt.Sprintf("unknown mode %q (valid: %s, %s)", *mode, config.ModeFanout, config.ModeRoundRobin)))
+		os.Exit(exitUsage)
+	}
+	speed, err := session.ParseSpeed(*speedStr)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitUsage)
+	}
+
+	records, err := session.Load(path)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+
+	targets := make([]config.Target, len(targetURLs))
+	for i, u := range targetURLs {
+		targets[i] = config.Target{URL: u}
+	}
+	send, closeTargets, err := newTargetSend("replay", *mode, targets)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+	defer closeTargets()
+
+	stop := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		close(stop)
+	}()
+
+	display.Println(display.Info(fmt.Sprintf("replaying %d event(s) from %s at %s → %s",
+		len(records), path, *speedStr, strings.Join(targetURLs, ", "))))
+
+	replayer := &session.Replayer{
+		Speed: speed,
+		Send: func(rec session.Record) error {
+			display.Println(display.Info(fmt.Sprintf("%s replayed",
+				display.Highlight(fmt.Sprintf("%s:%s", rec.Event.Resource, rec.Event.Event)))))
+			err := send(rec.Event)
+			if err != nil {
+				display.Println(display.Error(fmt.Sprintf("replay error: %s", err.Error())))
+			}
+			return err
+		},
+	}
+	res := replayer.Run(records, stop)
+
+	display.Println(display.Info(fmt.Sprintf("replay finished: %d sent, %d failed", res.Sent, res.Failed)))
+	if res.Failed > 0 {
+		closeTargets()
+		os.Exit(exitFailure)
+	}
+}
+
+// newTargetSend builds a delivery function over targets for the chosen mode
+// (as a pipeline would forward) and a function that releases the targets.
+// In fanout mode the function returns once every target has answered.
+func newTargetSend(name, mode string, targets []config.Target) (func(config.WebhookEvent) error, func(), error) {
+	if mode == config.ModeRoundRobin {
+		b, err := forwarder.NewBalancer(name, targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		var once sync.Once
+		return b.Forward, func() { once.Do(b.Stop) }, nil
+	}
+
+	senders, err := forwarder.NewSenders(name, targets)
+	if err != nil {
+		return nil, nil, err
+	}
+	send := func(event config.WebhookEvent) error {
+		var wg sync.WaitGroup
+		errs := make([]error, len(senders))
+		for i, s := range senders {
+			wg.Add(1)
+			go func(i int, s forwarder.Sender) {
+				defer wg.Done()
+				errs[i] = s.Send(event)
+			}(i, s)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var once sync.Once
+	closeAll := func() {
+		once.Do(func() {
+			for _, s := range senders {
+				_ = s.Close()
+			}
+		})
+	}
+	return send, closeAll, nil
+}
+
+// parseWithPositional parses fs from args, allowing flags before and after
+// a single positional argument, which is returned.
+func parseWithPositional(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() == 0 {
+		return "", nil
+	}
+	positional := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return positional, nil
+}
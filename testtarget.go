@@ -0,0 +1,123 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+	"github.com/tejzpr/webex-go-hookbuster/internal/loadgen"
+)
+
+// maxResponseBody caps how much of an HTTP target's response is printed.
+const maxResponseBody = 512
+
+// runTestTarget implements "hookbuster test-target URL". It sends one
+// synthetic event to the target and reports how it answered: the status
+// and body for HTTP targets, or the acknowledgement for other kinds.
+func runTestTarget(args []string) {
+	fs := flag.NewFlagSet("test-target", flag.ExitOnError)
+	kindSpec := fs.String("event", "messages:created", "resource:event of the sample event")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for an HTTP response")
+	targetURL, err := parseWithPositional(fs, args)
+	if err != nil || targetURL == "" {
+		display.Println(display.Error("usage: hookbuster test-target URL [--event messages:created]"))
+		os.Exit(exitUsage)
+	}
+
+	kind, err := parseKind(*kindSpec)
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitUsage)
+	}
+	target := config.Target{URL: targetURL}
+	if err := config.ValidateTarget(target); err != nil {
+		display.Println(display.Error(fmt.Sprintf("target %q: %s", targetURL, err.Error())))
+		os.Exit(exitUsage)
+	}
+
+	event := loadgen.Synthesize(kind, 1, time.Now())
+	display.Println(display.Info(fmt.Sprintf("sending a sample %s event to %s", kind, display.Highlight(targetURL))))
+
+	if u, _ := url.Parse(targetURL); u.Scheme == "http" || u.Scheme == "https" {
+		err = postSample(targetURL, event, *timeout)
+	} else {
+		err = sendSample(target, event)
+	}
+	if err != nil {
+		display.Println(display.Error(err.Error()))
+		os.Exit(exitFailure)
+	}
+}
+
+// parseKind parses a resource:event pair that loadgen can synthesize.
+func parseKind(spec string) (loadgen.Kind, error) {
+	for _, k := range loadgen.AllKinds() {
+		if k.String() == spec {
+			return k, nil
+		}
+	}
+	return loadgen.Kind{}, fmt.Errorf("unknown event %q (e.g. messages:created, memberships:deleted)", spec)
+}
+
+// postSample POSTs the event like an HTTP target would and reports the
+// response. A status of 400 or above counts as a failure.
+func postSample(targetURL string, event config.WebhookEvent, timeout time.Duration) error {
+	data, err := json.MarshalIndent(event, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	start := time.Now()
+	resp, err := client.Post(targetURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	snippet := strings.TrimSpace(string(body))
+	if len(body) > maxResponseBody {
+		snippet = strings.TrimSpace(string(body[:maxResponseBody])) + "…"
+	}
+
+	display.Println(display.Info(fmt.Sprintf("%s in %s", resp.Status, fmtLatency(latency))))
+	if snippet != "" {
+		display.Println(display.Info(snippet))
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("target answered %s", resp.Status)
+	}
+	return nil
+}
+
+// sendSample delivers the event through the target kind's sender.
+func sendSample(target config.Target, event config.WebhookEvent) error {
+	s, err := forwarder.NewSender("test-target", target)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	start := time.Now()
+	if err := s.Send(event); err != nil {
+		return fmt.Errorf("send failed after %s: %w", fmtLatency(time.Since(start)), err)
+	}
+	display.Println(display.Info(fmt.Sprintf("delivered in %s", fmtLatency(time.Since(start)))))
+	return nil
+}
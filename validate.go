@@ -0,0 +1,130 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/forwarder"
+)
+
+// runValidate implements "hookbuster validate". It loads the config, checks
+// that every referenced env var is set and builds each target's sender
+// without connecting, reporting every problem rather than stopping at the
+// first. Warnings are printed but do not fail validation. It exits with
+// exitConfig if anything is wrong.
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := fs.String("c", "", "path to hookbuster.yml config file (default $HOOKBUSTER_CONFIG)")
+	_ = fs.Parse(args)
+
+	if *configPath == "" {
+		*configPath = os.Getenv("HOOKBUSTER_CONFIG")
+	}
+	if *configPath == "" {
+		display.Println(display.Error("usage: hookbuster validate -c hookbuster.yml"))
+		os.Exit(exitUsage)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		n := printConfigError(*configPath, err)
+		display.Println(display.Error(fmt.Sprintf("%s has %d problem(s)", *configPath, n)))
+		os.Exit(exitConfig)
+	}
+	printConfigWarnings(*configPath, cfg)
+
+	problems := checkConfig(cfg)
+	for _, p := range problems {
+		display.Println(display.Error(p))
+	}
+	if len(problems) > 0 {
+		display.Println(display.Error(fmt.Sprintf("%s has %d problem(s)", *configPath, len(problems))))
+		os.Exit(exitConfig)
+	}
+
+	targets := 0
+	for _, p := range cfg.Pipelines {
+		targets += len(p.Targets)
+	}
+	display.Println(display.Info(fmt.Sprintf("%s is valid: %d pipeline(s), %d target(s)", *configPath, len(cfg.Pipelines), targets)))
+}
+
+// checkConfig returns the problems a loaded config would hit at startup:
+// unset token and secret env vars (including attachment storage keys), and
+// targets whose sender cannot be built.
+func checkConfig(cfg *config.HookbusterConfig) []string {
+	var problems []string
+	unset := func(prefix, env string) {
+		if os.Getenv(env) == "" {
+			problems = append(problems, fmt.Sprintf("%s: env var %s is not set", prefix, env))
+		}
+	}
+
+	if cfg.Server != nil {
+		for _, env := range cfg.Server.TokenEnvs {
+			unset("server", env)
+		}
+	}
+
+	for _, p := range cfg.Pipelines {
+		unset(fmt.Sprintf("pipeline %q", p.Name), p.TokenEnv)
+		for _, env := range p.Attachments.SecretEnvs() {
+			unset(fmt.Sprintf("pipeline %q: attachments", p.Name), env)
+		}
+		for _, t := range p.Targets {
+			prefix := fmt.Sprintf("pipeline %q: target %q", p.Name, t.URL)
+			for _, env := range t.SecretEnvs() {
+				unset(prefix, env)
+			}
+			if err := checkTarget(p.Name, t); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", prefix, err.Error()))
+			}
+		}
+	}
+	return problems
+}
+
+// checkTarget validates a target and builds its sender without connecting:
+// sender constructors only parse options, and connections are opened on
+// the first send.
+func checkTarget(pipeline string, t config.Target) error {
+	if err := config.ValidateTarget(t); err != nil {
+		return err
+	}
+	s, err := forwarder.NewSender(pipeline, t)
+	if err != nil {
+		return err
+	}
+	return s.Close()
+}
+
+// printConfigError prints a LoadConfig error, one line per problem and
+// warning when it is a validation error, and returns the number of problems
+// printed.
+func printConfigError(path string, err error) int {
+	var verr *config.ValidationError
+	if !errors.As(err, &verr) {
+		display.Println(display.Error(err.Error()))
+		return 1
+	}
+	printConfigWarnings(path, &config.HookbusterConfig{Warnings: verr.Warnings})
+	for _, p := range verr.Problems {
+		display.Println(display.Error(fmt.Sprintf("%s: %s", path, p)))
+	}
+	return len(verr.Problems)
+}
+
+// printConfigWarnings prints the warnings found while loading a config.
+func printConfigWarnings(path string, cfg *config.HookbusterConfig) {
+	for _, w := range cfg.Warnings {
+		display.Println(display.Warning(fmt.Sprintf("%s: warning: %s", path, w)))
+	}
+}
@@ -0,0 +1,67 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/listener"
+)
+
+// runWhoami implements "hookbuster whoami". It verifies the token in
+// --token-env, or every pipeline's token with -c, and prints the identity
+// behind it.
+func runWhoami(args []string) {
+	fs := flag.NewFlagSet("whoami", flag.ExitOnError)
+	tokenEnv := fs.String("token-env", "TOKEN", "env var holding the Webex access token")
+	configPath := fs.String("c", "", "check the token of every pipeline in this hookbuster.yml instead")
+	_ = fs.Parse(args)
+
+	type check struct {
+		label    string
+		tokenEnv string
+	}
+	var checks []check
+	if *configPath != "" {
+		cfg, err := config.LoadConfig(*configPath)
+		if err != nil {
+			printConfigError(*configPath, err)
+			os.Exit(exitConfig)
+		}
+		for _, p := range cfg.Pipelines {
+			checks = append(checks, check{label: fmt.Sprintf("[%s] ", p.Name), tokenEnv: p.TokenEnv})
+		}
+	} else {
+		checks = append(checks, check{tokenEnv: *tokenEnv})
+	}
+
+	code := 0
+	for _, c := range checks {
+		token := os.Getenv(c.tokenEnv)
+		if token == "" {
+			display.Println(display.Error(fmt.Sprintf("%senv var %s is not set", c.label, c.tokenEnv)))
+			code = exitConfig
+			continue
+		}
+		person, err := listener.VerifyAccessToken(token)
+		if err != nil {
+			display.Println(display.Error(c.label + err.Error()))
+			if code == 0 {
+				code = exitFailure
+			}
+			continue
+		}
+		display.Println(display.Info(fmt.Sprintf("%s%s <%s>", c.label, display.Highlight(person.DisplayName), strings.Join(person.Emails, ", "))))
+		display.Println(display.Info(fmt.Sprintf("%sid: %s  org: %s  type: %s", c.label, person.ID, person.OrgID, person.Type)))
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
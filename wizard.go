@@ -0,0 +1,219 @@
+/* SPDX-License-Identifier: MPL-2.0
+ * Copyright 2025 Tejus Pratap <tejzpr@gmail.com>
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/tejzpr/webex-go-hookbuster/internal/cli"
+	"github.com/tejzpr/webex-go-hookbuster/internal/config"
+	"github.com/tejzpr/webex-go-hookbuster/internal/display"
+	"github.com/tejzpr/webex-go-hookbuster/internal/listener"
+)
+
+// defaultConfigPath is where the wizard offers to save its config.
+const defaultConfigPath = "hookbuster.yml"
+
+// runInteractiveMode is the setup wizard. It collects one or more
+// pipelines (token, target URLs, mode, resources and events), offers to
+// save them as a hookbuster.yml for "hookbuster run -c", then runs them.
+// Tokens are only held in memory; the saved file names the env var each
+// token will be read from.
+func runInteractiveMode() {
+	var pipelines []config.Pipeline
+	tokens := make(map[string]string)
+
+	for {
+		p, sel, token := gatherPipeline(len(pipelines)+1, pipelines)
+		p.SetSelection(sel)
+		pipelines = append(pipelines, p)
+		tokens[p.Name] = token
+
+		if !gatherConfirm("Add another pipeline?", false) {
+			break
+		}
+	}
+
+	offerSave(&config.HookbusterConfig{Pipelines: pipelines})
+
+	var listeners []*listener.Listener
+	for _, p := range pipelines {
+		listeners = append(listeners, startPipeline(p, p.Selection(), tokens[p.Name], nil, nil))
+	}
+	waitForMultiShutdown(listeners)
+}
+
+// gatherPipeline prompts for every setting of the n-th pipeline and returns
+// it with its subscriptions and verified token.
+func gatherPipeline(n int, existing []config.Pipeline) (config.Pipeline, config.Selection, string) {
+	display.Println(display.Info(fmt.Sprintf("── pipeline %d ──", n)))
+	var p config.Pipeline
+
+	retryWithValidation(
+		func() (string, error) { return cli.RequestPipelineName(fmt.Sprintf("pipeline-%d", n)) },
+		func(name string) error {
+			for _, e := range existing {
+				if e.Name == name {
+					return fmt.Errorf("a pipeline named %q already exists", name)
+				}
+			}
+			return nil
+		},
+		func(name string) { p.Name = name },
+	)
+
+	token := gatherToken()
+
+	retryWithValidation(
+		func() (string, error) { return cli.RequestTokenEnv(defaultTokenEnv(p.Name)) },
+		nil,
+		func(env string) { p.TokenEnv = env },
+	)
+
+	p.Targets = gatherTargets(p.Name)
+	if len(p.Targets) > 1 {
+		retryWithValidation(cli.RequestMode, nil, func(mode string) {
+			display.Println(display.Answer(mode))
+			p.Mode = mode
+		})
+	}
+
+	return p, gatherSelection(), token
+}
+
+// nonEnvChars matches runs of characters not allowed in env var names.
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// defaultTokenEnv derives a token env var name from a pipeline name, e.g.
+// "main-account" becomes WEBEX_TOKEN_MAIN_ACCOUNT.
+func defaultTokenEnv(pipeline string) string {
+	suffix := strings.Trim(nonEnvChars.ReplaceAllString(strings.ToUpper(pipeline), "_"), "_")
+	if suffix == "" {
+		return "WEBEX_TOKEN"
+	}
+	return "WEBEX_TOKEN_" + suffix
+}
+
+// gatherTargets prompts for target URLs until an empty answer, requiring at
+// least one. Each URL is checked like "hookbuster validate" would.
+func gatherTargets(pipeline string) []config.Target {
+	var targets []config.Target
+	for {
+		var target config.Target
+		done := false
+		retryWithValidation(
+			cli.RequestTargetURL,
+			func(u string) error {
+				if u == "" {
+					if len(targets) == 0 {
+						return fmt.Errorf("at least one target is required")
+					}
+					return nil
+				}
+				for _, t := range targets {
+					if t.URL == u {
+						return fmt.Errorf("%s is already a target of this pipeline", u)
+					}
+				}
+				return checkTarget(pipeline, config.Target{URL: u})
+			},
+			func(u string) {
+				if u == "" {
+					done = true
+					return
+				}
+				display.Println(display.Answer(u))
+				target = config.Target{URL: u}
+			},
+		)
+		if done {
+			return targets
+		}
+		targets = append(targets, target)
+	}
+}
+
+// gatherSelection prompts for one or more resources and, unless all
+// resources were chosen, the events wanted from each.
+func gatherSelection() config.Selection {
+	var result *cli.ResourceResult
+	retryWithValidation(cli.RequestResources, nil, func(r *cli.ResourceResult) { result = r })
+
+	var sel config.Selection
+	if result.AllResources {
+		display.Println(display.Answer("all"))
+		for _, res := range result.Resources {
+			sel = append(sel, config.Subscription{Resource: res.Description, Events: []string{"all"}})
+		}
+		return sel
+	}
+
+	for _, res := range result.Resources {
+		display.Println(display.Answer(res.Description))
+		events := []string{"all"}
+		// Resources with a single event have nothing to choose
+		if len(res.Events) > 1 {
+			retryWithValidation(
+				func() ([]string, error) { return cli.RequestEvents(res) },
+				nil,
+				func(e []string) { events = e },
+			)
+		}
+		display.Println(display.Answer(strings.ToUpper(strings.Join(events, ", "))))
+		sel = append(sel, config.Subscription{Resource: res.Description, Events: events})
+	}
+	return sel
+}
+
+// offerSave asks whether to write cfg to a file and, if so, where. The file
+// is validated before it is written; a failed save is reported but does not
+// stop the session.
+func offerSave(cfg *config.HookbusterConfig) {
+	if !gatherConfirm(fmt.Sprintf("Save this setup as a config file for %s?", display.Highlight("hookbuster run -c")), true) {
+		return
+	}
+
+	var path string
+	retryWithValidation(
+		func() (string, error) { return cli.RequestPath("Config file path", defaultConfigPath) },
+		func(p string) error {
+			if _, err := os.Stat(p); err == nil && !gatherConfirm(fmt.Sprintf("%s exists. Overwrite it?", p), false) {
+				return fmt.Errorf("choose another path")
+			}
+			return nil
+		},
+		func(p string) { path = p },
+	)
+
+	if err := config.SaveConfig(path, cfg); err != nil {
+		display.Println(display.Error(err.Error()))
+		return
+	}
+
+	display.Println(display.Info(fmt.Sprintf("saved %d pipeline(s) to %s", len(cfg.Pipelines), display.Highlight(path))))
+	display.Println(display.Info("to run it again, set the token env vars and start hookbuster with the file:"))
+	seen := make(map[string]bool)
+	for _, p := range cfg.Pipelines {
+		if !seen[p.TokenEnv] {
+			seen[p.TokenEnv] = true
+			display.Println(display.Info(fmt.Sprintf("  export %s=<token for %s>", p.TokenEnv, p.Name)))
+		}
+	}
+	display.Println(display.Info(fmt.Sprintf("  hookbuster run -c %s", path)))
+}
+
+// gatherConfirm asks a yes/no question until it gets a valid answer.
+func gatherConfirm(question string, def bool) bool {
+	var yes bool
+	retryWithValidation(
+		func() (bool, error) { return cli.Confirm(question, def) },
+		nil,
+		func(answer bool) { yes = answer },
+	)
+	return yes
+}